@@ -35,7 +35,7 @@ curl -s "${AB_BASE_URL}/session/v1/admin/namespaces/$AB_NAMESPACE/configuration"
 
 echo Creating rule sets ...
 
-curl -s "${AB_BASE_URL}/match2/v1/namespaces/$AB_NAMESPACE/rulesets" -H "Authorization: Bearer $ACCESS_TOKEN" -H 'Content-Type: application/json' -d "{\"data\":{\"shipCountMin\":2,\"shipCountMax\":2},\"name\":\"${DEMO_PREFIX}_ruleset\"}"
+curl -s "${AB_BASE_URL}/match2/v1/namespaces/$AB_NAMESPACE/rulesets" -H "Authorization: Bearer $ACCESS_TOKEN" -H 'Content-Type: application/json' -d "{\"data\":{\"match_logic\":\"crew\",\"crewType\":\"solo\",\"shipCountMin\":2,\"shipCountMax\":2},\"name\":\"${DEMO_PREFIX}_ruleset\"}"
 
 echo Registering match function \(replace exising\) $NGROK_URL ...
 
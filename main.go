@@ -103,15 +103,24 @@ func main() {
 
 	if strings.ToLower(server.GetEnv("PLUGIN_GRPC_SERVER_AUTH_ENABLED", "false")) == "true" {
 		refreshInterval := server.GetEnvInt("REFRESH_INTERVAL", 600)
-		configRepo := sdkAuth.DefaultConfigRepositoryImpl()
-		tokenRepo := sdkAuth.DefaultTokenRepositoryImpl()
-		authService := iam.OAuth20Service{
-			Client:           factory.NewIamClient(configRepo),
-			ConfigRepository: configRepo,
-			TokenRepository:  tokenRepo,
+		if jwksSource := server.GetEnv("AUTH_JWKS", ""); jwksSource != "" {
+			// verify tokens offline against the given key set instead of asking IAM
+			server.Validator = server.NewLocalTokenValidator(jwksSource, time.Duration(refreshInterval)*time.Second)
+		} else {
+			configRepo := sdkAuth.DefaultConfigRepositoryImpl()
+			tokenRepo := sdkAuth.DefaultTokenRepositoryImpl()
+			authService := iam.OAuth20Service{
+				Client:           factory.NewIamClient(configRepo),
+				ConfigRepository: configRepo,
+				TokenRepository:  tokenRepo,
+			}
+			server.Validator = validator.NewTokenValidator(authService, time.Duration(refreshInterval)*time.Second)
 		}
-		server.Validator = validator.NewTokenValidator(authService, time.Duration(refreshInterval)*time.Second)
 		server.Validator.Initialize()
+		server.Policy, err = server.LoadAuthPolicy()
+		if err != nil {
+			logrus.Fatalf("failed to load the auth policy: %v", err)
+		}
 
 		unaryServerInterceptors = append(unaryServerInterceptors, server.UnaryAuthServerIntercept)
 		streamServerInterceptors = append(streamServerInterceptors, server.StreamAuthServerIntercept)
@@ -124,11 +133,17 @@ func main() {
 		grpc.ChainStreamInterceptor(streamServerInterceptors...),
 	)
 
-	//create game matchmaker
-	gameMM := server.NewGameMatchmaker()
+	//create the match logics, the rules json or the match pool picks the one handling a call
+	registry := server.NewDefaultRegistry()
+	if err := registry.SetDefault(server.GetEnv("MATCH_LOGIC_DEFAULT", server.MatchLogicGame)); err != nil {
+		logrus.Fatalf("failed to set the default match logic: %v", err)
+	}
+	if err := registry.AssignPools(server.GetEnv("MATCH_POOL_LOGICS", "")); err != nil {
+		logrus.Fatalf("failed to assign match pools: %v", err)
+	}
 	matchfunctiongrpc.RegisterMatchFunctionServer(gameServer, &server.MatchFunctionServer{
 		UnimplementedMatchFunctionServer: matchfunctiongrpc.UnimplementedMatchFunctionServer{},
-		MM:                               gameMM,
+		Registry:                         registry,
 	})
 
 	logrus.Infof("adding the grpc reflection.")
@@ -148,6 +163,7 @@ func main() {
 		collectors.NewGoCollector(),
 		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
 	)
+	promRegistry.MustRegister(server.Collectors()...)
 
 	go func() {
 		http.Handle("/metrics", promhttp.HandlerFor(promRegistry, promhttp.HandlerOpts{}))
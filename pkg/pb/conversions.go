@@ -218,3 +218,40 @@ func MatchfunctionBackfillTicketToProtoBackfillTicket(backfillTicket matchmaker.
 		MatchSessionId: backfillTicket.MatchSessionID,
 	}
 }
+
+// ProtoBackfillTicketToMatchfunctionBackfillTicket will convert a proto backfill ticket to a matchmaker backfill ticket
+func ProtoBackfillTicketToMatchfunctionBackfillTicket(backfillTicket *BackfillTicket) matchmaker.BackfillTicket {
+	partialMatch := backfillTicket.GetPartialMatch()
+	return matchmaker.BackfillTicket{
+		TicketID:  backfillTicket.TicketId,
+		MatchPool: backfillTicket.MatchPool,
+		CreatedAt: backfillTicket.CreatedAt.AsTime(),
+		PartialMatch: matchmaker.Match{
+			Tickets: pie_.Map(partialMatch.GetTickets(), ProtoTicketToMatchfunctionTicket),
+			Teams: pie_.Map(partialMatch.GetTeams(), func(team *BackfillTicket_Team) matchmaker.Team {
+				return matchmaker.Team{UserIDs: pie_.Map(team.UserIds, player.IDFromString)}
+			}),
+			RegionPreference: partialMatch.GetRegionPreferences(),
+			MatchAttributes:  partialMatch.GetMatchAttributes().AsMap(),
+			Backfill:         partialMatch.GetBackfill(),
+			ServerName:       partialMatch.GetServerName(),
+			ClientVersion:    partialMatch.GetClientVersion(),
+		},
+		MatchSessionID: backfillTicket.MatchSessionId,
+	}
+}
+
+// MatchfunctionBackfillProposalToProtoBackfillProposal will convert a matchmaker backfill proposal to a proto backfill proposal
+func MatchfunctionBackfillProposalToProtoBackfillProposal(proposal matchmaker.BackfillProposal) *BackfillProposal {
+	return &BackfillProposal{
+		BackfillTicketId: proposal.BackfillTicketID,
+		CreatedAt:        timestamppb.New(proposal.CreatedAt),
+		AddedTickets:     pie_.Map(proposal.AddedTickets, MatchfunctionTicketToProtoTicket),
+		ProposedTeams: pie_.Map(proposal.ProposedTeams, func(team matchmaker.Team) *BackfillProposal_Team {
+			return &BackfillProposal_Team{UserIds: pie_.Map(team.UserIDs, player.IDToString)}
+		}),
+		ProposalId:     proposal.ProposalID,
+		MatchPool:      proposal.MatchPool,
+		MatchSessionId: proposal.MatchSessionID,
+	}
+}
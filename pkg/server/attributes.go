@@ -0,0 +1,150 @@
+// Copyright (c) 2023 AccelByte Inc. All Rights Reserved.
+// This is licensed software from AccelByte Inc, for limitations
+// and restrictions contact your company contract manager.
+
+package server
+
+import (
+	"fmt"
+	"math"
+
+	"matchmaking-function-grpc-plugin-server-go/pkg/matchmaker"
+)
+
+// Kinds of AttributeRule
+const (
+	AttributeEqual     = "equal"
+	AttributeIntersect = "intersect"
+	AttributeDistance  = "distance"
+)
+
+// Sources an AttributeRule reads its attribute from
+const (
+	AttributeSourceTicket = "ticket"
+	AttributeSourcePlayer = "player"
+)
+
+// values returns the attribute of the ticket, or of each of its players, a missing attribute being nil
+func (r AttributeRule) values(ticket matchmaker.Ticket) []interface{} {
+	if r.Source != AttributeSourcePlayer {
+		return []interface{}{ticket.TicketAttributes[r.Attribute]}
+	}
+	values := make([]interface{}, 0, len(ticket.Players))
+	for _, p := range ticket.Players {
+		values = append(values, p.Attributes[r.Attribute])
+	}
+	return values
+}
+
+// compatible reports whether the values may share a match under the rule
+func (r AttributeRule) compatible(values []interface{}) bool {
+	switch r.Type {
+	case AttributeEqual:
+		return equalValues(values)
+	case AttributeIntersect:
+		return intersectingValues(values)
+	case AttributeDistance:
+		return closeValues(values, r.MaxDistance)
+	default:
+		return true
+	}
+}
+
+// allows reports whether the tickets may share a match under the rule
+func (r AttributeRule) allows(tickets []matchmaker.Ticket) bool {
+	var values []interface{}
+	for _, ticket := range tickets {
+		values = append(values, r.values(ticket)...)
+	}
+	return r.compatible(values)
+}
+
+// attributesAllow reports whether the tickets may share a match under every rule
+func attributesAllow(rules []AttributeRule, tickets []matchmaker.Ticket) bool {
+	for _, rule := range rules {
+		if !rule.allows(tickets) {
+			return false
+		}
+	}
+	return true
+}
+
+// equalValues reports whether the values are all the same, a missing value only equals another missing one
+func equalValues(values []interface{}) bool {
+	for _, value := range values {
+		if fmt.Sprintf("%#v", value) != fmt.Sprintf("%#v", values[0]) {
+			return false
+		}
+	}
+	return true
+}
+
+// intersectingValues reports whether the values, each a string or a list of them, have a string in common
+func intersectingValues(values []interface{}) bool {
+	var common map[string]bool
+	for _, value := range values {
+		set := stringSet(value)
+		if common == nil {
+			common = set
+			continue
+		}
+		for s := range common {
+			if !set[s] {
+				delete(common, s)
+			}
+		}
+	}
+	return common == nil || len(common) > 0
+}
+
+// stringSet returns the strings of a value that is a string or a list of them
+func stringSet(value interface{}) map[string]bool {
+	set := map[string]bool{}
+	switch value := value.(type) {
+	case string:
+		set[value] = true
+	case []string:
+		for _, s := range value {
+			set[s] = true
+		}
+	case []interface{}:
+		for _, s := range value {
+			if s, ok := s.(string); ok {
+				set[s] = true
+			}
+		}
+	}
+	return set
+}
+
+// closeValues reports whether the values are all numbers at most maxDistance apart
+func closeValues(values []interface{}, maxDistance float64) bool {
+	low, high := math.Inf(1), math.Inf(-1)
+	for _, value := range values {
+		number, ok := numberValue(value)
+		if !ok {
+			return false
+		}
+		low, high = math.Min(low, number), math.Max(high, number)
+	}
+	return len(values) == 0 || high-low <= maxDistance
+}
+
+// attributeWindow is the ticketFilter keeping the attribute of every ticket of a match compatible under the rule
+type attributeWindow struct {
+	rule   AttributeRule
+	values []interface{}
+}
+
+func newAttributeWindow(rule AttributeRule, seed matchmaker.Ticket) *attributeWindow {
+	return &attributeWindow{rule: rule, values: rule.values(seed)}
+}
+
+func (w *attributeWindow) fits(ticket matchmaker.Ticket) bool {
+	values := append(append(make([]interface{}, 0, len(w.values)+len(ticket.Players)), w.values...), w.rule.values(ticket)...)
+	return w.rule.compatible(values)
+}
+
+func (w *attributeWindow) add(ticket matchmaker.Ticket) {
+	w.values = append(w.values, w.rule.values(ticket)...)
+}
@@ -0,0 +1,178 @@
+// Copyright (c) 2023 AccelByte Inc. All Rights Reserved.
+// This is licensed software from AccelByte Inc, for limitations
+// and restrictions contact your company contract manager.
+
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"matchmaking-function-grpc-plugin-server-go/pkg/matchmaker"
+	"matchmaking-function-grpc-plugin-server-go/pkg/player"
+)
+
+func newAttributeTicket(playerID string, attributes map[string]interface{}) matchmaker.Ticket {
+	ticket := newTestTicket(playerID)
+	ticket.TicketAttributes = attributes
+	return ticket
+}
+
+func newPlayerAttributeTicket(attributes ...map[string]interface{}) matchmaker.Ticket {
+	ticket := newTestTicket()
+	for i, playerAttributes := range attributes {
+		ticket.Players = append(ticket.Players, player.PlayerData{PlayerID: player.ID(rune('a' + i)), Attributes: playerAttributes})
+	}
+	return ticket
+}
+
+func TestAttributeRuleAllows(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    AttributeRule
+		tickets []matchmaker.Ticket
+		want    bool
+	}{
+		{
+			name: "equal",
+			rule: AttributeRule{Attribute: "gameMode", Type: AttributeEqual},
+			tickets: []matchmaker.Ticket{
+				newAttributeTicket("a", map[string]interface{}{"gameMode": "ctf"}),
+				newAttributeTicket("b", map[string]interface{}{"gameMode": "ctf"}),
+			},
+			want: true,
+		},
+		{
+			name: "not equal",
+			rule: AttributeRule{Attribute: "gameMode", Type: AttributeEqual},
+			tickets: []matchmaker.Ticket{
+				newAttributeTicket("a", map[string]interface{}{"gameMode": "ctf"}),
+				newAttributeTicket("b", map[string]interface{}{"gameMode": "koth"}),
+			},
+		},
+		{
+			name: "missing is not equal",
+			rule: AttributeRule{Attribute: "gameMode", Type: AttributeEqual},
+			tickets: []matchmaker.Ticket{
+				newAttributeTicket("a", map[string]interface{}{"gameMode": "ctf"}),
+				newAttributeTicket("b", nil),
+			},
+		},
+		{
+			name: "intersecting lists",
+			rule: AttributeRule{Attribute: "languages", Type: AttributeIntersect},
+			tickets: []matchmaker.Ticket{
+				newAttributeTicket("a", map[string]interface{}{"languages": []interface{}{"en", "de"}}),
+				newAttributeTicket("b", map[string]interface{}{"languages": []interface{}{"fr", "de"}}),
+				newAttributeTicket("c", map[string]interface{}{"languages": "de"}),
+			},
+			want: true,
+		},
+		{
+			name: "disjoint lists",
+			rule: AttributeRule{Attribute: "languages", Type: AttributeIntersect},
+			tickets: []matchmaker.Ticket{
+				newAttributeTicket("a", map[string]interface{}{"languages": []interface{}{"en", "de"}}),
+				newAttributeTicket("b", map[string]interface{}{"languages": []interface{}{"fr"}}),
+			},
+		},
+		{
+			name: "players within distance",
+			rule: AttributeRule{Attribute: "level", Type: AttributeDistance, Source: AttributeSourcePlayer, MaxDistance: 5},
+			tickets: []matchmaker.Ticket{
+				newPlayerAttributeTicket(map[string]interface{}{"level": float64(10)}, map[string]interface{}{"level": float64(13)}),
+				newPlayerAttributeTicket(map[string]interface{}{"level": float64(15)}),
+			},
+			want: true,
+		},
+		{
+			name: "players too far apart",
+			rule: AttributeRule{Attribute: "level", Type: AttributeDistance, Source: AttributeSourcePlayer, MaxDistance: 5},
+			tickets: []matchmaker.Ticket{
+				newPlayerAttributeTicket(map[string]interface{}{"level": float64(10)}, map[string]interface{}{"level": float64(16)}),
+			},
+		},
+		{
+			name: "player without number",
+			rule: AttributeRule{Attribute: "level", Type: AttributeDistance, Source: AttributeSourcePlayer, MaxDistance: 5},
+			tickets: []matchmaker.Ticket{
+				newPlayerAttributeTicket(map[string]interface{}{"level": float64(10)}, map[string]interface{}{"level": "ten"}),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// act
+			got := tt.rule.allows(tt.tickets)
+
+			// assert
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestGameMatchMakerAttributeRules(t *testing.T) {
+	// prepare
+	rules := GameRules{
+		AllianceRule:   AllianceRule{PlayerMinNumber: 2, PlayerMaxNumber: 2},
+		AttributeRules: []AttributeRule{{Attribute: "gameMode", Type: AttributeEqual}},
+	}
+	tickets := []matchmaker.Ticket{
+		newAttributeTicket("c1", map[string]interface{}{"gameMode": "ctf"}),
+		newAttributeTicket("k1", map[string]interface{}{"gameMode": "koth"}),
+		newAttributeTicket("c2", map[string]interface{}{"gameMode": "ctf"}),
+	}
+
+	// act
+	matches := makeTestMatches(t, GameMatchMaker{}, rules, tickets)
+
+	// assert
+	if assert.Len(t, matches, 1) {
+		assert.ElementsMatch(t, []player.ID{"c1", "c2"}, matches[0].Teams[0].UserIDs)
+	}
+}
+
+func TestMatchMakerAttributeRules(t *testing.T) {
+	// prepare
+	rules := GameRules{AttributeRules: []AttributeRule{{Attribute: "languages", Type: AttributeIntersect}}}
+	tickets := []matchmaker.Ticket{
+		newAttributeTicket("en", map[string]interface{}{"languages": []interface{}{"en"}}),
+		newAttributeTicket("fr", map[string]interface{}{"languages": []interface{}{"fr"}}),
+		newAttributeTicket("enfr", map[string]interface{}{"languages": []interface{}{"fr", "en"}}),
+	}
+
+	// act
+	matches := makeTestMatches(t, MatchMaker{}, rules, tickets)
+
+	// assert
+	if assert.Len(t, matches, 1) {
+		assert.Equal(t, []player.ID{"en", "enfr"}, matches[0].Teams[0].UserIDs)
+	}
+}
+
+func TestProposeBackfillAttributeRules(t *testing.T) {
+	// prepare
+	backfillTicket := matchmaker.BackfillTicket{
+		TicketID: GenerateUUID(),
+		PartialMatch: matchmaker.Match{
+			Tickets: []matchmaker.Ticket{newAttributeTicket("a1", map[string]interface{}{"gameMode": "ctf"})},
+			Teams:   []matchmaker.Team{{UserIDs: []player.ID{"a1"}}},
+		},
+	}
+	koth := newAttributeTicket("k1", map[string]interface{}{"gameMode": "koth"})
+	pool := []matchmaker.Ticket{koth, newAttributeTicket("c1", map[string]interface{}{"gameMode": "ctf"})}
+	rules := GameRules{
+		AllianceRule:   AllianceRule{PlayerMaxNumber: 3},
+		AttributeRules: []AttributeRule{{Attribute: "gameMode", Type: AttributeEqual}},
+	}
+
+	// act
+	proposal, remaining := proposeBackfill(NewScope(context.Background(), ""), backfillTicket, pool, rules)
+
+	// assert
+	if assert.NotNil(t, proposal) {
+		assert.Equal(t, []matchmaker.Team{{UserIDs: []player.ID{"a1", "c1"}}}, proposal.ProposedTeams)
+	}
+	assert.Equal(t, []string{koth.TicketID}, ticketIDs(remaining))
+}
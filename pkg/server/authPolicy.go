@@ -0,0 +1,140 @@
+// Copyright (c) 2023 AccelByte Inc. All Rights Reserved.
+// This is licensed software from AccelByte Inc, for limitations
+// and restrictions contact your company contract manager.
+
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/AccelByte/accelbyte-go-sdk/services-api/pkg/utils/auth/validator"
+)
+
+// namespacePlaceholder is replaced by the namespace of the service in the resources of a policy
+const namespacePlaceholder = "{namespace}"
+
+// MethodPermission is what a gRPC method requires of the caller's token. Public methods need no token at all
+type MethodPermission struct {
+	Public   bool   `json:"public"`
+	Resource string `json:"resource"`
+	Action   int    `json:"action"`
+}
+
+// AuthPolicy maps fully qualified gRPC methods, e.g. "/accelbyte.matchmaking.matchfunction.MatchFunction/MakeMatches",
+// to the permission they require. A "/package.Service/*" key covers every method of a service, and Default covers the
+// methods not listed
+type AuthPolicy struct {
+	Default MethodPermission            `json:"default"`
+	Methods map[string]MethodPermission `json:"methods"`
+}
+
+// Policy is the policy the auth interceptors enforce, DefaultAuthPolicy when nil
+var Policy *AuthPolicy
+
+// DefaultAuthPolicy requires the permission from AB_RESOURCE_NAME and AB_ACTION for every method, except for the
+// health checks and reflection which are public
+func DefaultAuthPolicy() *AuthPolicy {
+	return &AuthPolicy{
+		Default: MethodPermission{
+			Resource: fmt.Sprintf("NAMESPACE:%s:%s", namespacePlaceholder, getResourceName()),
+			Action:   getAction(),
+		},
+		Methods: map[string]MethodPermission{
+			"/grpc.health.v1.Health/*":                    {Public: true},
+			"/grpc.reflection.v1alpha.ServerReflection/*": {Public: true},
+			"/grpc.reflection.v1.ServerReflection/*":      {Public: true},
+		},
+	}
+}
+
+// LoadAuthPolicy reads the policy json from the file named by AUTH_POLICY_FILE, or from AUTH_POLICY itself. The
+// methods it lists are layered over DefaultAuthPolicy, whose default is kept when the policy has none
+func LoadAuthPolicy() (*AuthPolicy, error) {
+	policyJSON := []byte(GetEnv("AUTH_POLICY", ""))
+	if path := GetEnv("AUTH_POLICY_FILE", ""); path != "" {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("could not read the auth policy file: %w", err)
+		}
+		policyJSON = content
+	}
+	if len(bytes.TrimSpace(policyJSON)) == 0 {
+		return DefaultAuthPolicy(), nil
+	}
+	return ParseAuthPolicy(policyJSON)
+}
+
+// ParseAuthPolicy parses a policy json and layers it over DefaultAuthPolicy
+func ParseAuthPolicy(policyJSON []byte) (*AuthPolicy, error) {
+	var loaded AuthPolicy
+	decoder := json.NewDecoder(bytes.NewReader(policyJSON))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&loaded); err != nil {
+		return nil, fmt.Errorf("could not parse the auth policy: %w", err)
+	}
+
+	policy := DefaultAuthPolicy()
+	if loaded.Default != (MethodPermission{}) {
+		if err := loaded.Default.validate("default"); err != nil {
+			return nil, err
+		}
+		policy.Default = loaded.Default
+	}
+	for method, permission := range loaded.Methods {
+		if !strings.HasPrefix(method, "/") || strings.Count(method, "/") != 2 {
+			return nil, fmt.Errorf("auth policy method %q is not in the /package.Service/Method format", method)
+		}
+		if err := permission.validate(method); err != nil {
+			return nil, err
+		}
+		policy.Methods[method] = permission
+	}
+	return policy, nil
+}
+
+func (p MethodPermission) validate(name string) error {
+	if p.Public {
+		return nil
+	}
+	if p.Resource == "" {
+		return fmt.Errorf("auth policy %s needs a resource or to be public", name)
+	}
+	if p.Action <= 0 {
+		return fmt.Errorf("auth policy %s needs an action greater than 0", name)
+	}
+	return nil
+}
+
+// permission returns the permission required by fullMethod: the method's own entry, else its service's, else the
+// default
+func (p *AuthPolicy) permission(fullMethod string) MethodPermission {
+	if permission, ok := p.Methods[fullMethod]; ok {
+		return permission
+	}
+	if i := strings.LastIndex(fullMethod, "/"); i > 0 {
+		if permission, ok := p.Methods[fullMethod[:i]+"/*"]; ok {
+			return permission
+		}
+	}
+	return p.Default
+}
+
+// validatorPermission returns the permission in the form the token validator checks, for the given namespace
+func (p MethodPermission) validatorPermission(namespace string) validator.Permission {
+	return validator.Permission{
+		Action:   p.Action,
+		Resource: strings.ReplaceAll(p.Resource, namespacePlaceholder, namespace),
+	}
+}
+
+// currentPolicy returns Policy, or DefaultAuthPolicy when none was set
+func currentPolicy() *AuthPolicy {
+	if Policy == nil {
+		return DefaultAuthPolicy()
+	}
+	return Policy
+}
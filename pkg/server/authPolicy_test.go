@@ -0,0 +1,100 @@
+// Copyright (c) 2023 AccelByte Inc. All Rights Reserved.
+// This is licensed software from AccelByte Inc, for limitations
+// and restrictions contact your company contract manager.
+
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/AccelByte/accelbyte-go-sdk/services-api/pkg/utils/auth/validator"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const testPolicy = `{
+	"methods": {
+		"/accelbyte.matchmaking.matchfunction.MatchFunction/MakeMatches": {"resource": "NAMESPACE:{namespace}:MMV2GRPCSERVICE:MATCHES", "action": 4},
+		"/internal.Admin/*": {"resource": "ADMIN:NAMESPACE:{namespace}", "action": 15}
+	}
+}`
+
+func TestAuthPolicyPermission(t *testing.T) {
+	// prepare
+	policy, err := ParseAuthPolicy([]byte(testPolicy))
+	assert.Nil(t, err)
+
+	tests := []struct {
+		method   string
+		public   bool
+		resource string
+		action   int
+	}{
+		{method: "/grpc.health.v1.Health/Check", public: true},
+		{method: "/grpc.reflection.v1alpha.ServerReflection/ServerReflectionInfo", public: true},
+		{method: "/accelbyte.matchmaking.matchfunction.MatchFunction/MakeMatches", resource: "NAMESPACE:{namespace}:MMV2GRPCSERVICE:MATCHES", action: 4},
+		{method: "/accelbyte.matchmaking.matchfunction.MatchFunction/GetStatCodes", resource: "NAMESPACE:{namespace}:MMV2GRPCSERVICE", action: 2},
+		{method: "/internal.Admin/Reset", resource: "ADMIN:NAMESPACE:{namespace}", action: 15},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			// act
+			permission := policy.permission(tt.method)
+
+			// assert
+			assert.Equal(t, MethodPermission{Public: tt.public, Resource: tt.resource, Action: tt.action}, permission)
+		})
+	}
+}
+
+func TestParseAuthPolicyErrors(t *testing.T) {
+	tests := map[string]string{
+		"malformed":       `{"methods":`,
+		"unknown field":   `{"method": {}}`,
+		"bad method":      `{"methods": {"MakeMatches": {"public": true}}}`,
+		"no resource":     `{"methods": {"/a.B/C": {"action": 2}}}`,
+		"no action":       `{"methods": {"/a.B/C": {"resource": "NAMESPACE:{namespace}:X"}}}`,
+		"invalid default": `{"default": {"action": 2}}`,
+	}
+	for name, policyJSON := range tests {
+		t.Run(name, func(t *testing.T) {
+			// act
+			policy, err := ParseAuthPolicy([]byte(policyJSON))
+
+			// assert
+			assert.Nil(t, policy)
+			assert.NotNil(t, err)
+		})
+	}
+}
+
+func TestAuthServerInterceptPolicy(t *testing.T) {
+	// prepare
+	policy, _ := ParseAuthPolicy([]byte(testPolicy))
+	previous := Policy
+	Policy = policy
+	t.Cleanup(func() { Policy = previous })
+	t.Setenv("AB_NAMESPACE", "mygame")
+	fake := &fakeValidator{}
+	useValidator(t, fake)
+	handler := func(srv interface{}, stream grpc.ServerStream) error { return nil }
+	withToken := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer abc"))
+
+	// act
+	healthErr := StreamAuthServerIntercept(nil, &fakeServerStream{ctx: context.Background()},
+		&grpc.StreamServerInfo{FullMethod: "/grpc.health.v1.Health/Watch"}, handler)
+	noTokenErr := StreamAuthServerIntercept(nil, &fakeServerStream{ctx: context.Background()},
+		&grpc.StreamServerInfo{FullMethod: "/accelbyte.matchmaking.matchfunction.MatchFunction/MakeMatches"}, handler)
+	matchesErr := StreamAuthServerIntercept(nil, &fakeServerStream{ctx: withToken},
+		&grpc.StreamServerInfo{FullMethod: "/accelbyte.matchmaking.matchfunction.MatchFunction/MakeMatches"}, handler)
+
+	// assert
+	assert.Nil(t, healthErr)
+	assert.Equal(t, codes.Unauthenticated, status.Code(noTokenErr))
+	assert.Nil(t, matchesErr)
+	assert.Equal(t, []validator.Permission{{Resource: "NAMESPACE:mygame:MMV2GRPCSERVICE:MATCHES", Action: 4}}, fake.permissions)
+}
@@ -6,13 +6,13 @@ package server
 
 import (
 	"context"
-	"fmt"
 	"strings"
 
 	"github.com/AccelByte/accelbyte-go-sdk/services-api/pkg/utils/auth/validator"
-	"github.com/pkg/errors"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 )
 
 var (
@@ -20,23 +20,7 @@ var (
 )
 
 func UnaryAuthServerIntercept(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
-	if Validator == nil {
-		return nil, errors.New("server token validator not set")
-	}
-
-	meta, found := metadata.FromIncomingContext(ctx)
-	if !found {
-		return nil, errors.New("metadata missing")
-	}
-
-	authorization := meta["authorization"][0]
-	token := strings.TrimPrefix(authorization, "Bearer ")
-
-	namespace := getNamespace()
-	permission := getRequiredPermission()
-	var userId *string
-
-	err := Validator.Validate(token, &permission, &namespace, userId)
+	ctx, err := authorize(ctx, info.FullMethod)
 	if err != nil {
 		return nil, err
 	}
@@ -45,28 +29,75 @@ func UnaryAuthServerIntercept(ctx context.Context, req interface{}, info *grpc.U
 }
 
 func StreamAuthServerIntercept(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
-	if Validator == nil {
-		return errors.New("server token validator not set")
+	ctx, err := authorize(ss.Context(), info.FullMethod)
+	if err != nil {
+		return err
 	}
 
-	meta, found := metadata.FromIncomingContext(ss.Context())
-	if !found {
-		return errors.New("metadata missing")
+	return handler(srv, &callerStream{ServerStream: ss, ctx: ctx})
+}
+
+// authorize validates the bearer token of the call against the permission the policy requires for fullMethod, and
+// returns ctx carrying the caller's claims
+func authorize(ctx context.Context, fullMethod string) (context.Context, error) {
+	required := currentPolicy().permission(fullMethod)
+	if required.Public {
+		return ctx, nil
 	}
 
-	authorization := meta["authorization"][0]
-	token := strings.TrimPrefix(authorization, "Bearer ")
+	if Validator == nil {
+		return nil, status.Error(codes.Internal, "server token validator not set")
+	}
+
+	token, err := bearerToken(ctx)
+	if err != nil {
+		return nil, err
+	}
 
 	namespace := getNamespace()
-	permission := getRequiredPermission()
+	permission := required.validatorPermission(namespace)
 	var userId *string
 
-	err := Validator.Validate(token, &permission, &namespace, userId)
+	err = Validator.Validate(token, &permission, &namespace, userId)
 	if err != nil {
-		return err
+		return nil, authError(err, permission.Resource)
+	}
+
+	claims, err := callerClaims(token)
+	if err != nil {
+		return nil, unauthenticated(ReasonTokenInvalid, "invalid token claims: %s", err)
+	}
+
+	return ContextWithCaller(ctx, claims), nil
+}
+
+// bearerToken returns the token of the single authorization header of the call. The header must use the Bearer
+// scheme, matched regardless of case, anything else is Unauthenticated
+func bearerToken(ctx context.Context) (string, error) {
+	meta, found := metadata.FromIncomingContext(ctx)
+	if !found {
+		return "", unauthenticated(ReasonTokenMissing, "metadata missing")
+	}
+
+	values := meta.Get("authorization")
+	switch len(values) {
+	case 0:
+		return "", unauthenticated(ReasonTokenMissing, "authorization header missing")
+	case 1:
+	default:
+		return "", unauthenticated(ReasonTokenInvalid, "expected one authorization header, got %d", len(values))
+	}
+
+	scheme, token, ok := strings.Cut(strings.TrimSpace(values[0]), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", unauthenticated(ReasonTokenInvalid, "authorization header must use the Bearer scheme")
+	}
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return "", unauthenticated(ReasonTokenMissing, "bearer token missing")
 	}
 
-	return handler(srv, ss)
+	return token, nil
 }
 
 func getAction() int {
@@ -80,10 +111,3 @@ func getNamespace() string {
 func getResourceName() string {
 	return GetEnv("AB_RESOURCE_NAME", "MMV2GRPCSERVICE")
 }
-
-func getRequiredPermission() validator.Permission {
-	return validator.Permission{
-		Action:   getAction(),
-		Resource: fmt.Sprintf("NAMESPACE:%s:%s", getNamespace(), getResourceName()),
-	}
-}
@@ -5,69 +5,121 @@
 package server
 
 import (
-	"fmt"
+	"context"
+	"errors"
 	"testing"
-	"time"
 
-	"github.com/AccelByte/accelbyte-go-sdk/services-api/pkg/factory"
-	"github.com/AccelByte/accelbyte-go-sdk/services-api/pkg/service/iam"
-	sdkAuth "github.com/AccelByte/accelbyte-go-sdk/services-api/pkg/utils/auth"
 	"github.com/AccelByte/accelbyte-go-sdk/services-api/pkg/utils/auth/validator"
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 )
 
-type MyConfigRepo struct {
-	baseUrl      string
-	clientId     string
-	clientSecret string
+type fakeValidator struct {
+	err         error
+	claims      JWTClaims
+	tokens      []string
+	permissions []validator.Permission
 }
 
-func (c *MyConfigRepo) GetClientId() string       { return c.clientId }
-func (c *MyConfigRepo) GetClientSecret() string   { return c.clientSecret }
-func (c *MyConfigRepo) GetJusticeBaseUrl() string { return c.baseUrl }
-
-func TestTokenValidator_ValidateToken(t *testing.T) {
-	// Arrange
-	baseUrl := "https://development.accelbyte.io"
-	clientId := "4eb9c4f862fe452e8d675c3030f25912" // TODO mock and remove hardcode
-	clientSecret := ""
-	configRepo := &MyConfigRepo{
-		baseUrl:      baseUrl,
-		clientId:     clientId,
-		clientSecret: clientSecret,
-	}
-	tokenRepo := sdkAuth.DefaultTokenRepositoryImpl()
-	authService := iam.OAuth20Service{
-		Client:           factory.NewIamClient(configRepo),
-		ConfigRepository: configRepo,
-		TokenRepository:  tokenRepo,
-	}
+func (f *fakeValidator) Initialize() {}
 
-	err := authService.LoginClient(&clientId, &clientSecret)
-	if err != nil {
-		assert.Fail(t, err.Error())
-		return
-	}
+func (f *fakeValidator) Validate(token string, permission *validator.Permission, namespace *string, userId *string) error {
+	f.tokens = append(f.tokens, token)
+	f.permissions = append(f.permissions, *permission)
+	return f.err
+}
+
+func (f *fakeValidator) Claims(token string) (*JWTClaims, error) {
+	claims := f.claims
+	return &claims, nil
+}
+
+type fakeServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (f *fakeServerStream) Context() context.Context {
+	return f.ctx
+}
 
-	accessToken, err := authService.GetToken()
-	if err != nil {
-		assert.Fail(t, err.Error())
-		return
+func useValidator(t *testing.T, v validator.AuthTokenValidator) {
+	previous := Validator
+	Validator = v
+	t.Cleanup(func() { Validator = previous })
+}
+
+func TestAuthServerIntercept(t *testing.T) {
+	tests := []struct {
+		name         string
+		md           metadata.MD
+		validatorErr error
+		code         codes.Code
+		token        string
+	}{
+		{name: "bearer", md: metadata.Pairs("authorization", "Bearer abc"), code: codes.OK, token: "abc"},
+		{name: "scheme case", md: metadata.Pairs("authorization", "bearer abc"), code: codes.OK, token: "abc"},
+		{name: "header case", md: metadata.Pairs("Authorization", "BEARER  abc "), code: codes.OK, token: "abc"},
+		{name: "no metadata", md: nil, code: codes.Unauthenticated},
+		{name: "no header", md: metadata.Pairs("x-other", "value"), code: codes.Unauthenticated},
+		{name: "multiple headers", md: metadata.Pairs("authorization", "Bearer abc", "authorization", "Bearer def"), code: codes.Unauthenticated},
+		{name: "basic scheme", md: metadata.Pairs("authorization", "Basic dXNlcjpwYXNz"), code: codes.Unauthenticated},
+		{name: "no scheme", md: metadata.Pairs("authorization", "abc"), code: codes.Unauthenticated},
+		{name: "empty token", md: metadata.Pairs("authorization", "Bearer "), code: codes.Unauthenticated},
+		{name: "invalid token", md: metadata.Pairs("authorization", "Bearer abc"), validatorErr: errors.New("token was revoked"), code: codes.Unauthenticated, token: "abc"},
+		{name: "insufficient permissions", md: metadata.Pairs("authorization", "Bearer abc"), validatorErr: errors.New("insufficient permissions"), code: codes.PermissionDenied, token: "abc"},
 	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// prepare
+			fake := &fakeValidator{err: tt.validatorErr}
+			useValidator(t, fake)
+			ctx := context.Background()
+			if tt.md != nil {
+				ctx = metadata.NewIncomingContext(ctx, tt.md)
+			}
+			handled := 0
+
+			// act
+			_, unaryErr := UnaryAuthServerIntercept(ctx, nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, req interface{}) (interface{}, error) {
+				handled++
+				return nil, nil
+			})
+			streamErr := StreamAuthServerIntercept(nil, &fakeServerStream{ctx: ctx}, &grpc.StreamServerInfo{}, func(srv interface{}, stream grpc.ServerStream) error {
+				handled++
+				return nil
+			})
 
-	namespace := "accelbyte"
-	resourceName := "MMV2GRPCSERVICE"
-	requiredPermission := validator.Permission{
-		Action:   2,
-		Resource: fmt.Sprintf("NAMESPACE:%s:%s", namespace, resourceName),
+			// assert
+			assert.Equal(t, tt.code, status.Code(unaryErr))
+			assert.Equal(t, tt.code, status.Code(streamErr))
+			if tt.code == codes.OK {
+				assert.Equal(t, 2, handled)
+			} else {
+				assert.Equal(t, 0, handled)
+			}
+			if tt.token != "" {
+				assert.Equal(t, []string{tt.token, tt.token}, fake.tokens)
+			} else {
+				assert.Empty(t, fake.tokens)
+			}
+		})
 	}
+}
 
-	tokenValidator := validator.NewTokenValidator(authService, time.Hour)
-	tokenValidator.Initialize()
+func TestAuthServerInterceptWithoutValidator(t *testing.T) {
+	// prepare
+	useValidator(t, nil)
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer abc"))
 
-	// Act
-	err = tokenValidator.Validate(accessToken, &requiredPermission, &namespace, nil)
+	// act
+	_, err := UnaryAuthServerIntercept(ctx, nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, nil
+	})
 
-	// Assert
-	assert.Nil(t, err)
+	// assert
+	assert.Equal(t, codes.Internal, status.Code(err))
 }
@@ -0,0 +1,180 @@
+// Copyright (c) 2023 AccelByte Inc. All Rights Reserved.
+// This is licensed software from AccelByte Inc, for limitations
+// and restrictions contact your company contract manager.
+
+package server
+
+import (
+	"time"
+
+	pie_ "github.com/elliotchance/pie/v2"
+	"matchmaking-function-grpc-plugin-server-go/pkg/matchmaker"
+	"matchmaking-function-grpc-plugin-server-go/pkg/player"
+)
+
+// collectBackfillPool drains the ticket provider until both the ticket and the backfill ticket channels are closed,
+// or until the scope is cancelled
+func collectBackfillPool(scope *Scope, ticketProvider TicketProvider) ([]matchmaker.Ticket, []matchmaker.BackfillTicket) {
+	var pool []matchmaker.Ticket
+	var backfillTickets []matchmaker.BackfillTicket
+	nextTicket := ticketProvider.GetTickets()
+	nextBackfillTicket := ticketProvider.GetBackfillTickets()
+	for nextTicket != nil || nextBackfillTicket != nil {
+		select {
+		case ticket, ok := <-nextTicket:
+			if !ok {
+				nextTicket = nil
+				continue
+			}
+			pool = append(pool, ticket)
+		case backfillTicket, ok := <-nextBackfillTicket:
+			if !ok {
+				nextBackfillTicket = nil
+				continue
+			}
+			backfillTickets = append(backfillTickets, backfillTicket)
+		case <-scope.Ctx.Done():
+			scope.Log.Info("BACKFILL: CTX Done triggered")
+			return nil, nil
+		}
+	}
+	scope.Log.Infof("BACKFILL POOL: %d tickets, %d backfill tickets", len(pool), len(backfillTickets))
+
+	return pool, backfillTickets
+}
+
+// buildBackfill proposes pool tickets for each backfill ticket in turn, a ticket is only ever proposed once
+func buildBackfill(scope *Scope, backfillTickets []matchmaker.BackfillTicket, pool []matchmaker.Ticket, results chan matchmaker.BackfillProposal, rules GameRules) {
+	for _, backfillTicket := range backfillTickets {
+		var proposal *matchmaker.BackfillProposal
+		proposal, pool = proposeBackfill(scope, backfillTicket, pool, rules)
+		if proposal == nil {
+			scope.Log.Infof("BACKFILL: nothing to propose for backfill ticket %s", backfillTicket.TicketID)
+			continue
+		}
+		scope.Log.Infof("BACKFILL PROPOSAL SENT TO RESULTS: %+v", *proposal)
+		select {
+		case results <- *proposal:
+		case <-scope.Ctx.Done():
+			return
+		}
+	}
+}
+
+// proposeBackfill seats the pool tickets the rules allow on the partial match, or returns nil, and the tickets left
+func proposeBackfill(scope *Scope, backfillTicket matchmaker.BackfillTicket, pool []matchmaker.Ticket, rules GameRules) (*matchmaker.BackfillProposal, []matchmaker.Ticket) {
+	_, teamMax := teamCountRange(rules.AllianceRule)
+	teams := pie_.Map(backfillTicket.PartialMatch.Teams, func(team matchmaker.Team) matchmaker.Team {
+		return matchmaker.Team{UserIDs: append([]player.ID{}, team.UserIDs...)}
+	})
+	for len(teams) < teamMax {
+		teams = append(teams, matchmaker.Team{UserIDs: []player.ID{}})
+	}
+	teamLimits := make([]*partyLimitWindow, len(teams))
+	for team := range teamLimits {
+		teamLimits[team] = newPartyLimitWindow(rules.PartyRule)
+	}
+	partyTeams := map[string]int{}
+	seated, _ := joinParties(backfillTicket.PartialMatch.Tickets)
+	for _, party := range seated {
+		team := teamOfPlayers(teams, party.Players)
+		if team < 0 {
+			continue
+		}
+		teamLimits[team].add(party)
+		for _, key := range partyKeys(party) {
+			partyTeams[key] = team
+		}
+	}
+
+	pool, joined := joinParties(pool)
+	matchTickets := append([]matchmaker.Ticket{}, backfillTicket.PartialMatch.Tickets...)
+	var addedTickets []matchmaker.Ticket
+	var remaining []matchmaker.Ticket
+	for _, ticket := range pool {
+		team := partyTeam(partyTeams, joined.keys(ticket))
+		switch {
+		case !inBackfillPartition(scope, backfillTicket, ticket):
+			team = -1
+		case !backfillAllows(scope, rules.VersionRule, backfillTicket, matchTickets, ticket):
+			team = -1
+		case !attributesAllow(rules.AttributeRules, append(matchTickets, ticket)):
+			team = -1
+		case team < 0:
+			team = openestTeam(teams, ticket, rules.AllianceRule.PlayerMaxNumber, teamLimits)
+		case len(teams[team].UserIDs)+len(ticket.Players) > rules.AllianceRule.PlayerMaxNumber || !teamLimits[team].fits(ticket):
+			team = -1
+		}
+		if team < 0 {
+			remaining = append(remaining, ticket)
+			continue
+		}
+		teams[team].UserIDs = append(teams[team].UserIDs, pie_.Map(ticket.Players, player.ToID)...)
+		teamLimits[team].add(ticket)
+		matchTickets = append(matchTickets, ticket)
+		addedTickets = append(addedTickets, ticket)
+	}
+
+	remaining = joined.expand(remaining)
+	if len(addedTickets) == 0 {
+		return nil, remaining
+	}
+
+	teams = pie_.Filter(teams, func(team matchmaker.Team) bool {
+		return len(team.UserIDs) > 0
+	})
+
+	return newBackfillProposal(backfillTicket, joined.expand(addedTickets), teams), remaining
+}
+
+// inBackfillPartition reports whether the ticket is in the partition of the partial match, counting the pairing as
+// rejected when it is not. A match without tickets only has the match pool of its backfill ticket to go by
+func inBackfillPartition(scope *Scope, backfillTicket matchmaker.BackfillTicket, ticket matchmaker.Ticket) bool {
+	if len(backfillTicket.PartialMatch.Tickets) > 0 {
+		return samePartition(scope, backfillTicket.PartialMatch.Tickets[0], ticket)
+	}
+	if backfillTicket.MatchPool != "" && ticket.MatchPool != backfillTicket.MatchPool {
+		scope.rejectPairing("match_pool", backfillTicket.TicketID, ticket.TicketID)
+		return false
+	}
+	return true
+}
+
+// partyTeam returns the team already seating one of the parties, or -1
+func partyTeam(partyTeams map[string]int, keys []string) int {
+	for _, key := range keys {
+		if team, ok := partyTeams[key]; ok {
+			return team
+		}
+	}
+	return -1
+}
+
+// newBackfillProposal builds the proposal answering a backfill ticket with the added tickets and the resulting teams
+func newBackfillProposal(backfillTicket matchmaker.BackfillTicket, addedTickets []matchmaker.Ticket, proposedTeams []matchmaker.Team) *matchmaker.BackfillProposal {
+	return &matchmaker.BackfillProposal{
+		BackfillTicketID: backfillTicket.TicketID,
+		CreatedAt:        time.Now(),
+		AddedTickets:     addedTickets,
+		ProposedTeams:    proposedTeams,
+		ProposalID:       GenerateUUID(),
+		MatchPool:        backfillTicket.MatchPool,
+		MatchSessionID:   backfillTicket.MatchSessionID,
+	}
+}
+
+// openestTeam returns the index of the team with the most open slots that can still take the ticket's players within
+// its party limits, or -1
+func openestTeam(teams []matchmaker.Team, ticket matchmaker.Ticket, teamPlayerMax int, teamLimits []*partyLimitWindow) int {
+	best := -1
+	for i, team := range teams {
+		if len(team.UserIDs)+len(ticket.Players) > teamPlayerMax || !teamLimits[i].fits(ticket) {
+			continue
+		}
+		if best < 0 || len(team.UserIDs) < len(teams[best].UserIDs) {
+			best = i
+		}
+	}
+
+	return best
+}
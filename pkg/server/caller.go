@@ -0,0 +1,92 @@
+// Copyright (c) 2023 AccelByte Inc. All Rights Reserved.
+// This is licensed software from AccelByte Inc, for limitations
+// and restrictions contact your company contract manager.
+
+package server
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+)
+
+type callerKey struct{}
+
+// claimsProvider is implemented by the validators that can hand out the claims of a token they verified
+type claimsProvider interface {
+	Claims(token string) (*JWTClaims, error)
+}
+
+// ContextWithCaller returns ctx carrying the validated claims of the caller
+func ContextWithCaller(ctx context.Context, claims *JWTClaims) context.Context {
+	return context.WithValue(ctx, callerKey{}, claims)
+}
+
+// CallerFromContext returns the validated claims of the caller, absent when auth is disabled or the method is public
+func CallerFromContext(ctx context.Context) (*JWTClaims, bool) {
+	claims, ok := ctx.Value(callerKey{}).(*JWTClaims)
+	return claims, ok && claims != nil
+}
+
+// callerClaims returns the claims of a token the Validator already accepted
+func callerClaims(token string) (*JWTClaims, error) {
+	if provider, ok := Validator.(claimsProvider); ok {
+		return provider.Claims(token)
+	}
+	return unverifiedClaims(token)
+}
+
+// unverifiedClaims decodes the payload of a token without checking its signature, only to be used on tokens the
+// Validator accepted
+func unverifiedClaims(token string) (*JWTClaims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("token is not a compact JWS")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return nil, err
+	}
+	var claims JWTClaims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, err
+	}
+	return &claims, nil
+}
+
+// callerLog returns the log entry of a unary call, tagged with the client that made it when known
+func callerLog(ctx context.Context) *logrus.Entry {
+	entry := logrus.NewEntry(logrus.StandardLogger())
+	if claims, ok := CallerFromContext(ctx); ok {
+		entry = entry.WithField("clientID", claims.ClientID)
+	}
+	return entry
+}
+
+// checkCallerNamespace rejects a ticket of another namespace than the caller's. Calls without a caller, with auth
+// disabled, are not checked
+func checkCallerNamespace(ctx context.Context, ticketNamespace string) error {
+	claims, ok := CallerFromContext(ctx)
+	if !ok || ticketNamespace == "" {
+		return nil
+	}
+	if ticketNamespace == claims.Namespace || ticketNamespace == claims.StudioNamespace {
+		return nil
+	}
+	return namespaceMismatch(ticketNamespace, claims.Namespace)
+}
+
+// callerStream is the ServerStream whose context carries the caller
+type callerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *callerStream) Context() context.Context {
+	return s.ctx
+}
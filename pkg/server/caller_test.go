@@ -0,0 +1,157 @@
+// Copyright (c) 2023 AccelByte Inc. All Rights Reserved.
+// This is licensed software from AccelByte Inc, for limitations
+// and restrictions contact your company contract manager.
+
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"matchmaking-function-grpc-plugin-server-go/pkg/matchmaker"
+	matchfunctiongrpc "matchmaking-function-grpc-plugin-server-go/pkg/pb"
+	"matchmaking-function-grpc-plugin-server-go/pkg/player"
+)
+
+func TestAuthServerInterceptCaller(t *testing.T) {
+	// prepare
+	useValidator(t, &fakeValidator{claims: JWTClaims{Namespace: "mygame", ClientID: "platform"}})
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer abc"))
+	var unaryCaller, streamCaller *JWTClaims
+
+	// act
+	_, unaryErr := UnaryAuthServerIntercept(ctx, nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, req interface{}) (interface{}, error) {
+		unaryCaller, _ = CallerFromContext(ctx)
+		return nil, nil
+	})
+	streamErr := StreamAuthServerIntercept(nil, &fakeServerStream{ctx: ctx}, &grpc.StreamServerInfo{}, func(srv interface{}, stream grpc.ServerStream) error {
+		streamCaller = NewScope(stream.Context(), "trace").Caller
+		return nil
+	})
+
+	// assert
+	assert.Nil(t, unaryErr)
+	assert.Nil(t, streamErr)
+	if assert.NotNil(t, unaryCaller) && assert.NotNil(t, streamCaller) {
+		assert.Equal(t, "platform", unaryCaller.ClientID)
+		assert.Equal(t, "mygame", streamCaller.Namespace)
+	}
+}
+
+func TestUnverifiedClaims(t *testing.T) {
+	// prepare
+	key := newTestKey(t, "key-1")
+	token := key.sign(t, validTestClaims())
+
+	// act
+	claims, err := unverifiedClaims(token)
+	_, malformedErr := unverifiedClaims("abc")
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, "client", claims.ClientID)
+	assert.Equal(t, "user", claims.Subject)
+	assert.NotNil(t, malformedErr)
+}
+
+func TestValidateTicketCallerNamespace(t *testing.T) {
+	tests := []struct {
+		name      string
+		caller    *JWTClaims
+		namespace string
+		code      codes.Code
+	}{
+		{name: "no caller", namespace: "othergame", code: codes.OK},
+		{name: "same namespace", caller: &JWTClaims{Namespace: "mygame"}, namespace: "mygame", code: codes.OK},
+		{name: "studio namespace", caller: &JWTClaims{Namespace: "studio-game", StudioNamespace: "mygame"}, namespace: "mygame", code: codes.OK},
+		{name: "other namespace", caller: &JWTClaims{Namespace: "mygame"}, namespace: "othergame", code: codes.PermissionDenied},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// prepare
+			server := MatchFunctionServer{MM: New()}
+			ctx := context.Background()
+			if tt.caller != nil {
+				ctx = ContextWithCaller(ctx, tt.caller)
+			}
+			ticket := newTestTicket("a1")
+			ticket.Namespace = tt.namespace
+			req := &matchfunctiongrpc.ValidateTicketRequest{
+				Ticket: matchfunctiongrpc.MatchfunctionTicketToProtoTicket(ticket),
+				Rules:  &matchfunctiongrpc.Rules{Json: `{}`},
+			}
+
+			// act
+			_, err := server.ValidateTicket(ctx, req)
+
+			// assert
+			assert.Equal(t, tt.code, status.Code(err))
+		})
+	}
+}
+
+func TestBackfillMatchesCallerNamespace(t *testing.T) {
+	// prepare
+	server := MatchFunctionServer{MM: NewGameMatchmaker()}
+	ticket := newTestTicket("a1")
+	ticket.Namespace = "othergame"
+	stream := &fakeBackfillStream{
+		ctx: ContextWithCaller(context.Background(), &JWTClaims{Namespace: "mygame"}),
+		requests: []*matchfunctiongrpc.BackfillMakeMatchesRequest{
+			{RequestType: &matchfunctiongrpc.BackfillMakeMatchesRequest_Parameters{
+				Parameters: &matchfunctiongrpc.BackfillMakeMatchesRequest_MakeMatchesParameters{
+					Rules: &matchfunctiongrpc.Rules{Json: `{"alliance":{"player_max_number":2}}`},
+				},
+			}},
+			{RequestType: &matchfunctiongrpc.BackfillMakeMatchesRequest_Ticket{
+				Ticket: matchfunctiongrpc.MatchfunctionTicketToProtoTicket(ticket),
+			}},
+		},
+	}
+
+	// act
+	err := server.BackfillMatches(stream)
+
+	// assert
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+}
+
+func TestBackfillMatchesCallerNamespaceOfPartialMatch(t *testing.T) {
+	// prepare
+	server := MatchFunctionServer{MM: NewGameMatchmaker()}
+	matched := newTestTicket("a1")
+	matched.Namespace = "othergame"
+	backfillTicket := matchmaker.BackfillTicket{
+		TicketID:  GenerateUUID(),
+		MatchPool: "pool",
+		PartialMatch: matchmaker.Match{
+			Tickets: []matchmaker.Ticket{matched},
+			Teams:   []matchmaker.Team{{UserIDs: []player.ID{"a1"}}},
+		},
+	}
+	stream := &fakeBackfillStream{
+		ctx: ContextWithCaller(context.Background(), &JWTClaims{Namespace: "mygame"}),
+		requests: []*matchfunctiongrpc.BackfillMakeMatchesRequest{
+			{RequestType: &matchfunctiongrpc.BackfillMakeMatchesRequest_Parameters{
+				Parameters: &matchfunctiongrpc.BackfillMakeMatchesRequest_MakeMatchesParameters{
+					Rules: &matchfunctiongrpc.Rules{Json: `{"alliance":{"player_max_number":2}}`},
+				},
+			}},
+			{RequestType: &matchfunctiongrpc.BackfillMakeMatchesRequest_BackfillTicket{
+				BackfillTicket: matchfunctiongrpc.MatchfunctionBackfillTicketToProtoBackfillTicket(backfillTicket),
+			}},
+		},
+	}
+
+	// act
+	err := server.BackfillMatches(stream)
+
+	// assert
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+}
@@ -0,0 +1,168 @@
+// Copyright (c) 2023 AccelByte Inc. All Rights Reserved.
+// This is licensed software from AccelByte Inc, for limitations
+// and restrictions contact your company contract manager.
+
+package server
+
+import (
+	"matchmaking-function-grpc-plugin-server-go/pkg/matchmaker"
+	"matchmaking-function-grpc-plugin-server-go/pkg/player"
+)
+
+// rolesAttribute is the match attribute the assigned roles are written to, by player ID
+const rolesAttribute = "roles"
+
+// maxRoleSteps bounds the search for a role assignment of one team
+const maxRoleSteps = 10000
+
+// enabled reports whether the rule sets any role quota
+func (r CompositionRule) enabled() bool {
+	return len(r.Roles) > 0
+}
+
+// playerRoles returns the roles a player accepts, read from the rule's attribute as a single role or a list of them
+func (r CompositionRule) playerRoles(p player.PlayerData) []string {
+	switch value := p.Attributes[r.Attribute].(type) {
+	case string:
+		return []string{value}
+	case []string:
+		return value
+	case []interface{}:
+		roles := make([]string, 0, len(value))
+		for _, role := range value {
+			if role, ok := role.(string); ok {
+				roles = append(roles, role)
+			}
+		}
+		return roles
+	default:
+		return nil
+	}
+}
+
+// assign gives every player a role they accept, without going over the quotas' Max, so the Min of every quota can be
+// reached with at most capacity more players. It returns the roles in the order of the players, and false when no such
+// assignment exists
+func (r CompositionRule) assign(players []player.PlayerData, capacity int) ([]string, bool) {
+	quotas := map[string]int{}
+	for i, quota := range r.Roles {
+		quotas[quota.Role] = i
+	}
+	search := roleSearch{
+		rule:     r,
+		quotas:   quotas,
+		counts:   make([]int, len(r.Roles)),
+		accepted: make([][]int, len(players)),
+		assigned: make([]string, len(players)),
+		capacity: capacity,
+	}
+	for i, p := range players {
+		for _, role := range r.playerRoles(p) {
+			if quota, ok := quotas[role]; ok {
+				search.accepted[i] = append(search.accepted[i], quota)
+			}
+		}
+		if len(search.accepted[i]) == 0 {
+			return nil, false
+		}
+	}
+	if !search.next(0) {
+		return nil, false
+	}
+	return search.assigned, true
+}
+
+// roleSearch tries the roles each player accepts in turn, backtracking when a quota overflows
+type roleSearch struct {
+	rule     CompositionRule
+	quotas   map[string]int
+	counts   []int
+	accepted [][]int
+	assigned []string
+	capacity int
+	steps    int
+}
+
+func (s *roleSearch) next(i int) bool {
+	s.steps++
+	if s.steps > maxRoleSteps {
+		return false
+	}
+	if i == len(s.accepted) {
+		missing := 0
+		for quota, count := range s.counts {
+			if count < s.rule.Roles[quota].Min {
+				missing += s.rule.Roles[quota].Min - count
+			}
+		}
+		return missing <= s.capacity
+	}
+	for _, quota := range s.accepted[i] {
+		if s.counts[quota] >= s.rule.Roles[quota].Max {
+			continue
+		}
+		s.counts[quota]++
+		s.assigned[i] = s.rule.Roles[quota].Role
+		if s.next(i + 1) {
+			return true
+		}
+		s.counts[quota]--
+	}
+	return false
+}
+
+// assignTeams assigns the roles of every team by player ID, it returns false when a team does not meet the quotas
+func (r CompositionRule) assignTeams(teams [][]matchmaker.Ticket) (map[string]interface{}, bool) {
+	if !r.enabled() {
+		return nil, true
+	}
+	roles := map[string]interface{}{}
+	for _, team := range teams {
+		var players []player.PlayerData
+		for _, ticket := range team {
+			players = append(players, ticket.Players...)
+		}
+		assigned, ok := r.assign(players, 0)
+		if !ok {
+			return nil, false
+		}
+		for i, p := range players {
+			roles[player.IDToString(p.PlayerID)] = assigned[i]
+		}
+	}
+	return roles, true
+}
+
+// withRoles returns the match attributes with the assigned roles added
+func withRoles(attributes map[string]interface{}, roles map[string]interface{}) map[string]interface{} {
+	withRoles := map[string]interface{}{}
+	for key, value := range attributes {
+		withRoles[key] = value
+	}
+	withRoles[rolesAttribute] = roles
+	return withRoles
+}
+
+// roleWindow is the ticketFilter keeping one team able to meet the quotas of the rule once it holds playerMax players
+type roleWindow struct {
+	rule      CompositionRule
+	playerMax int
+	players   []player.PlayerData
+}
+
+func newRoleWindow(rule CompositionRule, playerMax int) *roleWindow {
+	return &roleWindow{rule: rule, playerMax: playerMax}
+}
+
+func (w *roleWindow) fits(ticket matchmaker.Ticket) bool {
+	if !w.rule.enabled() {
+		return true
+	}
+	players := append(append(make([]player.PlayerData, 0, len(w.players)+len(ticket.Players)), w.players...), ticket.Players...)
+	_, ok := w.rule.assign(players, w.playerMax-len(players))
+	return ok
+}
+
+func (w *roleWindow) add(ticket matchmaker.Ticket) {
+	w.players = append(w.players, ticket.Players...)
+}
@@ -0,0 +1,134 @@
+// Copyright (c) 2023 AccelByte Inc. All Rights Reserved.
+// This is licensed software from AccelByte Inc, for limitations
+// and restrictions contact your company contract manager.
+
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"matchmaking-function-grpc-plugin-server-go/pkg/matchmaker"
+	"matchmaking-function-grpc-plugin-server-go/pkg/player"
+)
+
+func newRolePlayers(roles ...interface{}) []player.PlayerData {
+	players := make([]player.PlayerData, 0, len(roles))
+	for i, role := range roles {
+		players = append(players, player.PlayerData{
+			PlayerID:   player.ID(string(rune('a' + i))),
+			Attributes: map[string]interface{}{"role": role},
+		})
+	}
+	return players
+}
+
+func TestCompositionRuleAssign(t *testing.T) {
+	rule := CompositionRule{Attribute: "role", Roles: []RoleQuota{
+		{Role: "tank", Min: 1, Max: 1},
+		{Role: "healer", Min: 1, Max: 1},
+		{Role: "damage", Min: 3, Max: 3},
+	}}
+	tests := []struct {
+		name     string
+		players  []player.PlayerData
+		capacity int
+		want     []string
+	}{
+		{
+			name:    "flexible players",
+			players: newRolePlayers("tank", []interface{}{"damage", "healer"}, "damage", "damage", []interface{}{"tank", "damage"}),
+			want:    []string{"tank", "healer", "damage", "damage", "damage"},
+		},
+		{name: "room for the missing roles", players: newRolePlayers("tank", "damage"), capacity: 3, want: []string{"tank", "damage"}},
+		{name: "not enough room", players: newRolePlayers("tank", "damage"), capacity: 2},
+		{name: "quota overflow", players: newRolePlayers("tank", "tank"), capacity: 3},
+		{name: "unknown role", players: newRolePlayers("bard"), capacity: 4},
+		{name: "no role", players: []player.PlayerData{{PlayerID: "a"}}, capacity: 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// act
+			got, ok := rule.assign(tt.players, tt.capacity)
+
+			// assert
+			assert.Equal(t, tt.want != nil, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func newRoleTicket(playerID string, role string) matchmaker.Ticket {
+	ticket := newTestTicket(playerID)
+	ticket.Players[0].Attributes = map[string]interface{}{"class": role}
+	return ticket
+}
+
+func TestGameMatchMakerComposition(t *testing.T) {
+	rules := GameRules{
+		AllianceRule: AllianceRule{MinNumber: 2, MaxNumber: 2, PlayerMinNumber: 3, PlayerMaxNumber: 3},
+		CompositionRule: CompositionRule{Attribute: "class", Roles: []RoleQuota{
+			{Role: "tank", Min: 1, Max: 1},
+			{Role: "damage", Min: 2, Max: 2},
+		}},
+	}
+
+	t.Run("one tank per team", func(t *testing.T) {
+		// prepare
+		tickets := []matchmaker.Ticket{
+			newRoleTicket("t1", "tank"),
+			newRoleTicket("t2", "tank"),
+			newRoleTicket("d1", "damage"),
+			newRoleTicket("d2", "damage"),
+			newRoleTicket("d3", "damage"),
+			newRoleTicket("d4", "damage"),
+		}
+
+		// act
+		matches := makeTestMatches(t, GameMatchMaker{}, rules, tickets)
+
+		// assert
+		if assert.Len(t, matches, 1) && assert.Len(t, matches[0].Teams, 2) {
+			assert.NotEqual(t, teamOf(matches[0], "t1"), teamOf(matches[0], "t2"))
+			assert.Equal(t, map[string]interface{}{
+				"t1": "tank", "t2": "tank", "d1": "damage", "d2": "damage", "d3": "damage", "d4": "damage",
+			}, matches[0].MatchAttributes[rolesAttribute])
+		}
+	})
+
+	t.Run("missing tank", func(t *testing.T) {
+		// prepare
+		tickets := []matchmaker.Ticket{
+			newRoleTicket("t1", "tank"),
+			newRoleTicket("d1", "damage"),
+			newRoleTicket("d2", "damage"),
+			newRoleTicket("d3", "damage"),
+			newRoleTicket("d4", "damage"),
+			newRoleTicket("d5", "damage"),
+		}
+
+		// act
+		matches := makeTestMatches(t, GameMatchMaker{}, rules, tickets)
+
+		// assert
+		assert.Empty(t, matches)
+	})
+}
+
+func TestMatchMakerComposition(t *testing.T) {
+	// prepare
+	rules := GameRules{CompositionRule: CompositionRule{Attribute: "class", Roles: []RoleQuota{
+		{Role: "tank", Min: 1, Max: 1},
+		{Role: "healer", Min: 1, Max: 1},
+	}}}
+	tickets := []matchmaker.Ticket{newRoleTicket("t1", "tank"), newRoleTicket("t2", "tank"), newRoleTicket("h1", "healer")}
+
+	// act
+	matches := makeTestMatches(t, MatchMaker{}, rules, tickets)
+
+	// assert
+	if assert.Len(t, matches, 1) {
+		assert.Equal(t, []player.ID{"t1", "h1"}, matches[0].Teams[0].UserIDs)
+		assert.Equal(t, map[string]interface{}{"t1": "tank", "h1": "healer"}, matches[0].MatchAttributes[rolesAttribute])
+	}
+}
@@ -0,0 +1,347 @@
+// Copyright (c) 2023 AccelByte Inc. All Rights Reserved.
+// This is licensed software from AccelByte Inc, for limitations
+// and restrictions contact your company contract manager.
+
+package server
+
+import (
+	"sort"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"matchmaking-function-grpc-plugin-server-go/pkg/matchmaker"
+)
+
+// alliancesAttribute is the match attribute listing the teams of every alliance, by team index
+const alliancesAttribute = "alliances"
+
+// crewSizes are the players a crew of each CrewType holds, as listed in the README
+var crewSizes = map[string]int{
+	"solo":       1,
+	"sloop":      2,
+	"brigantine": 3,
+	"galleon":    4,
+}
+
+// NewCrewMatchmaker returns a CrewMatchMaker of the MatchLogic interface
+func NewCrewMatchmaker() MatchLogic {
+	return CrewMatchMaker{}
+}
+
+// crewSize returns the players a crew holds, CrewSize when it is set, 0 for an unknown CrewType
+func (r GameRules) crewSize() int {
+	if r.CrewSize > 0 {
+		return r.CrewSize
+	}
+	return crewSizes[r.CrewType]
+}
+
+// crewRules returns the rules forming a single crew out of tickets, every other rule applying within the crew
+func (r GameRules) crewRules() GameRules {
+	crew := r
+	crew.AllianceRule = AllianceRule{MinNumber: 1, MaxNumber: 1, PlayerMinNumber: r.crewSize(), PlayerMaxNumber: r.crewSize()}
+	crew.Incremental = false
+	return crew
+}
+
+// crewsPerAlliance returns how many crews an alliance holds at least and at most, an empty alliance rule makes every
+// crew its own alliance
+func (r GameRules) crewsPerAlliance() (int, int) {
+	if r.AllianceRule.PlayerMaxNumber == 0 {
+		return 1, 1
+	}
+	size := r.crewSize()
+	perMin := (r.AllianceRule.PlayerMinNumber + size - 1) / size
+	if perMin < 1 {
+		perMin = 1
+	}
+	return perMin, r.AllianceRule.PlayerMaxNumber / size
+}
+
+// shipRange returns how many crews a match holds at least and at most. ShipCountMax defaults to what the alliance
+// rule seats, and ShipCountMin to ShipCountMax
+func (r GameRules) shipRange() (int, int) {
+	shipMax := r.ShipCountMax
+	if shipMax == 0 {
+		_, teamMax := teamCountRange(r.AllianceRule)
+		_, perMax := r.crewsPerAlliance()
+		shipMax = teamMax * perMax
+	}
+	shipMin := r.ShipCountMin
+	if shipMin == 0 {
+		shipMin = shipMax
+	}
+	return shipMin, shipMax
+}
+
+// alliances splits count crews into alliances per the alliance rule, as evenly as it can with the fewest alliances.
+// It returns the crew count of each alliance, or nil when the rule cannot seat count crews
+func (r GameRules) alliances(count int) []int {
+	perMin, perMax := r.crewsPerAlliance()
+	teamMin, teamMax := teamCountRange(r.AllianceRule)
+	if r.AllianceRule.PlayerMaxNumber == 0 {
+		teamMin, teamMax = count, count
+	}
+	for allianceCount := teamMin; allianceCount <= teamMax; allianceCount++ {
+		if allianceCount == 0 || count < allianceCount*perMin || count > allianceCount*perMax {
+			continue
+		}
+		crews := make([]int, allianceCount)
+		for i := range crews {
+			crews[i] = count / allianceCount
+			if i < count%allianceCount {
+				crews[i]++
+			}
+		}
+		return crews
+	}
+	return nil
+}
+
+// validateCrews additionally requires a known crew type or size and ship counts the alliance rule can seat, for the
+// crew logic
+func (r GameRules) validateCrews() error {
+	if _, ok := crewSizes[r.CrewType]; !ok && (r.CrewType != "" || r.CrewSize == 0) {
+		return invalidRules("crewType", "must be one of solo (1 player), sloop (2), brigantine (3) or galleon (4), or crewSize must be set, got %q", r.CrewType)
+	}
+	size := r.crewSize()
+	if r.ShipCountMax > 0 && r.ShipCountMin > r.ShipCountMax {
+		return invalidRules("shipCountMin", "must not be greater than shipCountMax (%d), got %d", r.ShipCountMax, r.ShipCountMin)
+	}
+	if r.ShipCountMax == 0 && r.AllianceRule.PlayerMaxNumber == 0 {
+		return invalidRules("shipCountMax", "is required when the alliance rule is empty")
+	}
+	if r.AllianceRule.PlayerMaxNumber > 0 && r.AllianceRule.PlayerMaxNumber < size {
+		return invalidRules("alliance.player_max_number", "must seat at least one %s crew of %d, got %d", r.CrewType, size, r.AllianceRule.PlayerMaxNumber)
+	}
+	shipMin, shipMax := r.shipRange()
+	for count := shipMin; count <= shipMax; count++ {
+		if r.alliances(count) != nil {
+			return nil
+		}
+	}
+	return invalidRules("alliance", "cannot seat between %d and %d crews", shipMin, shipMax)
+}
+
+// ValidateTicket rejects a ticket with more players than a crew holds
+func (c CrewMatchMaker) ValidateTicket(matchTicket matchmaker.Ticket, matchRules interface{}) (bool, error) {
+	logrus.Info("CREW MATCHMAKER: validate ticket")
+	rules, ok := matchRules.(GameRules)
+	if !ok {
+		return false, status.Error(codes.Internal, "invalid rules type for crew rules")
+	}
+	if len(matchTicket.Players) > rules.crewSize() {
+		return false, invalidArgument("ticket", "players", "has %d players, a %s crew holds %d", len(matchTicket.Players), rules.CrewType, rules.crewSize())
+	}
+	return true, nil
+}
+
+// EnrichTicket returns the ticket as it is, crews need nothing more than the players
+func (c CrewMatchMaker) EnrichTicket(matchTicket matchmaker.Ticket, ruleSet interface{}) (ticket matchmaker.Ticket, err error) {
+	logrus.Info("CREW MATCHMAKER: enrich ticket")
+	return matchTicket, nil
+}
+
+// GetStatCodes returns the stat codes declared by the rule set
+func (c CrewMatchMaker) GetStatCodes(matchRules interface{}) []string {
+	rules, ok := matchRules.(GameRules)
+	if !ok {
+		logrus.Error("invalid rules type for crew rules")
+		return []string{}
+	}
+	return rules.statCodes()
+}
+
+// RulesFromJSON returns the ruleset from the crew rules, which need a known crewType or a crewSize and ship counts the
+// alliance rule can seat
+func (c CrewMatchMaker) RulesFromJSON(jsonRules string) (interface{}, error) {
+	var ruleSet GameRules
+	if err := decodeRules(jsonRules, &ruleSet); err != nil {
+		return nil, err
+	}
+	if err := ruleSet.Validate(); err != nil {
+		return nil, err
+	}
+	if err := ruleSet.validateCrews(); err != nil {
+		return nil, err
+	}
+	return ruleSet, nil
+}
+
+// MakeMatches forms crews out of the tickets once the stream has ended, then groups the crews into matches of
+// alliances. Every crew is a team of the match, the alliances are listed in the "alliances" match attribute
+func (c CrewMatchMaker) MakeMatches(scope *Scope, ticketProvider TicketProvider, matchRules interface{}) <-chan matchmaker.Match {
+	scope.Log.Info("CREW MATCHMAKER: make matches")
+	results := make(chan matchmaker.Match)
+	rules, ok := matchRules.(GameRules)
+	if !ok {
+		scope.Log.Error("invalid rules type for crew rules")
+		close(results)
+		return results
+	}
+
+	go func() {
+		var unmatchedTickets []matchmaker.Ticket
+		tickets := ticketProvider.GetTickets()
+		for {
+			select {
+			case ticket, ok := <-tickets:
+				if !ok {
+					buildCrews(scope, unmatchedTickets, results, rules)
+					return
+				}
+				unmatchedTickets = append(unmatchedTickets, ticket)
+			case <-scope.Ctx.Done():
+				scope.Log.Info("CREW MATCHMAKER: CTX Done triggered")
+				close(results)
+				return
+			}
+		}
+	}()
+
+	return results
+}
+
+// BackfillMatches tops up the crews of the partial matches and adds crews up to the most ships a match holds
+func (c CrewMatchMaker) BackfillMatches(scope *Scope, ticketProvider TicketProvider, matchRules interface{}) <-chan matchmaker.BackfillProposal {
+	scope.Log.Info("CREW MATCHMAKER: backfill matches")
+	results := make(chan matchmaker.BackfillProposal)
+	rules, ok := matchRules.(GameRules)
+	if !ok {
+		scope.Log.Error("invalid rules type for crew rules")
+		close(results)
+		return results
+	}
+
+	_, shipMax := rules.shipRange()
+	backfillRules := rules
+	backfillRules.AllianceRule = AllianceRule{MinNumber: 1, MaxNumber: shipMax, PlayerMaxNumber: rules.crewSize()}
+	go func() {
+		defer close(results)
+		pool, backfillTickets := collectBackfillPool(scope, ticketProvider)
+		buildBackfill(scope, backfillTickets, pool, results, backfillRules)
+	}()
+
+	return results
+}
+
+// buildCrews forms the crews with the game logic, then fills matches with the oldest crews left that share the
+// partition, a region, compatible client versions and attributes with the oldest one, as many as the alliances seat
+func buildCrews(scope *Scope, unmatchedTickets []matchmaker.Ticket, results chan matchmaker.Match, rules GameRules) {
+	defer close(results)
+	var crews []matchmaker.Match
+	formGames(scope, unmatchedTickets, rules.crewRules(), func(crew matchmaker.Match) bool {
+		crews = append(crews, crew)
+		return true
+	})
+	scope.Log.Infof("CREW MATCHMAKER: %d crews formed", len(crews))
+	sortCrewsByAge(crews)
+
+	shipMin, shipMax := rules.shipRange()
+	for len(crews) > 0 {
+		group := []matchmaker.Match{crews[0]}
+		var rest []matchmaker.Match
+		for _, crew := range crews[1:] {
+			if len(group) < shipMax && crewsFit(scope, append(group[:len(group):len(group)], crew), rules) {
+				group = append(group, crew)
+				continue
+			}
+			rest = append(rest, crew)
+		}
+
+		count := len(group)
+		for count >= shipMin && rules.alliances(count) == nil {
+			count--
+		}
+		if count >= shipMin {
+			match := crewMatch(group[:count], rules.alliances(count), rules)
+			scope.Log.Infof("CREW MATCH SENT TO RESULTS: %+v", match)
+			select {
+			case results <- match:
+				observeWaitTimes(MatchLogicCrew, match.Tickets, time.Now())
+				observeQuality(MatchLogicCrew, match)
+			case <-scope.Ctx.Done():
+				scope.Log.Info("CREW MATCHMAKER: CTX Done triggered")
+				return
+			}
+			group = group[count:]
+		} else {
+			group = group[1:]
+		}
+		crews = append(append([]matchmaker.Match{}, group...), rest...)
+		sortCrewsByAge(crews)
+	}
+}
+
+// crewsFit reports whether the crews may share a match
+func crewsFit(scope *Scope, crews []matchmaker.Match, rules GameRules) bool {
+	var tickets []matchmaker.Ticket
+	for _, crew := range crews {
+		tickets = append(tickets, crew.Tickets...)
+	}
+	if !samePartition(scope, tickets[0], tickets[len(tickets)-1]) {
+		return false
+	}
+	if !versionsAllow(scope, rules.VersionRule, tickets) {
+		return false
+	}
+	if _, ok := matchRegions(tickets, rules.RegionRule, time.Now()); !ok {
+		return false
+	}
+	return attributesAllow(rules.AttributeRules, tickets)
+}
+
+// crewMatch builds the match of the crews, dealt into alliances of the given crew counts
+func crewMatch(crews []matchmaker.Match, allianceCrews []int, rules GameRules) matchmaker.Match {
+	var match matchmaker.Match
+	var roles map[string]interface{}
+	for _, crew := range crews {
+		match.Tickets = append(match.Tickets, crew.Tickets...)
+		match.Teams = append(match.Teams, crew.Teams...)
+		if crewRoles, ok := crew.MatchAttributes[rolesAttribute].(map[string]interface{}); ok {
+			if roles == nil {
+				roles = map[string]interface{}{}
+			}
+			for id, role := range crewRoles {
+				roles[id] = role
+			}
+		}
+	}
+	match.RegionPreference, _ = matchRegions(match.Tickets, rules.RegionRule, time.Now())
+	setServer(&match)
+
+	alliances := make([]interface{}, 0, len(allianceCrews))
+	team := 0
+	for _, count := range allianceCrews {
+		teams := make([]interface{}, 0, count)
+		for i := 0; i < count; i++ {
+			teams = append(teams, team)
+			team++
+		}
+		alliances = append(alliances, teams)
+	}
+	match.MatchAttributes = map[string]interface{}{alliancesAttribute: alliances}
+	if roles != nil {
+		match.MatchAttributes = withRoles(match.MatchAttributes, roles)
+	}
+	setQuality(&match, rules.crewRules(), time.Now())
+	return match
+}
+
+// sortCrewsByAge orders the crews by their oldest ticket, oldest first
+func sortCrewsByAge(crews []matchmaker.Match) {
+	oldest := func(crew matchmaker.Match) time.Time {
+		created := crew.Tickets[0].CreatedAt
+		for _, ticket := range crew.Tickets {
+			if ticket.CreatedAt.Before(created) {
+				created = ticket.CreatedAt
+			}
+		}
+		return created
+	}
+	sort.SliceStable(crews, func(i, j int) bool {
+		return oldest(crews[i]).Before(oldest(crews[j]))
+	})
+}
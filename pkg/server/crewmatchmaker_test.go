@@ -0,0 +1,133 @@
+// Copyright (c) 2023 AccelByte Inc. All Rights Reserved.
+// This is licensed software from AccelByte Inc, for limitations
+// and restrictions contact your company contract manager.
+
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"matchmaking-function-grpc-plugin-server-go/pkg/matchmaker"
+	"matchmaking-function-grpc-plugin-server-go/pkg/player"
+)
+
+func TestCrewRulesFromJSON(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules string
+		field string
+	}{
+		{name: "ships only", rules: `{"match_logic":"crew","crewType":"solo","shipCountMin":2,"shipCountMax":2}`},
+		{name: "alliances", rules: `{"crewType":"sloop","alliance":{"min_number":2,"max_number":2,"player_min_number":4,"player_max_number":4}}`},
+		{name: "crew size", rules: `{"crewSize":5,"shipCountMax":2}`},
+		{name: "crew size overrides the type", rules: `{"crewType":"sloop","crewSize":5,"shipCountMax":2}`},
+		{name: "unknown crew type", rules: `{"crewType":"raft","shipCountMax":2}`, field: "crewType"},
+		{name: "unknown crew type with a size", rules: `{"crewType":"raft","crewSize":5,"shipCountMax":2}`, field: "crewType"},
+		{name: "no crew type", rules: `{"shipCountMax":2}`, field: "crewType"},
+		{name: "negative crew size", rules: `{"crewSize":-1,"shipCountMax":2}`, field: "crewSize"},
+		{name: "ship min above max", rules: `{"crewType":"sloop","shipCountMin":3,"shipCountMax":2}`, field: "shipCountMin"},
+		{name: "no ship count", rules: `{"crewType":"sloop"}`, field: "shipCountMax"},
+		{name: "alliance below crew", rules: `{"crewType":"galleon","alliance":{"player_max_number":2}}`, field: "alliance.player_max_number"},
+		{name: "ships the alliances cannot seat", rules: `{"crewType":"sloop","shipCountMax":3,"alliance":{"min_number":2,"max_number":2,"player_min_number":4,"player_max_number":4}}`, field: "alliance"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// act
+			rules, err := NewCrewMatchmaker().RulesFromJSON(tt.rules)
+
+			// assert
+			if tt.field == "" {
+				assert.Nil(t, err)
+				assert.IsType(t, GameRules{}, rules)
+				return
+			}
+			assert.Nil(t, rules)
+			assert.Equal(t, codes.InvalidArgument, status.Code(err))
+			assert.Contains(t, status.Convert(err).Message(), tt.field)
+		})
+	}
+}
+
+func TestGameRulesAlliances(t *testing.T) {
+	// prepare
+	rules := GameRules{CrewType: "sloop", AllianceRule: AllianceRule{MinNumber: 2, MaxNumber: 3, PlayerMinNumber: 2, PlayerMaxNumber: 4}}
+
+	// act
+	tooFew := rules.alliances(1)
+	two := rules.alliances(3)
+	six := rules.alliances(6)
+	tooMany := rules.alliances(7)
+
+	// assert
+	assert.Nil(t, tooFew)
+	assert.Equal(t, []int{2, 1}, two)
+	assert.Equal(t, []int{2, 2, 2}, six)
+	assert.Nil(t, tooMany)
+}
+
+func TestCrewMatchMakerMakeMatches(t *testing.T) {
+	// prepare
+	rules := GameRules{
+		CrewType:     "sloop",
+		AllianceRule: AllianceRule{MinNumber: 2, MaxNumber: 2, PlayerMinNumber: 4, PlayerMaxNumber: 4},
+	}
+	tickets := []matchmaker.Ticket{
+		newTestTicket("s1"),
+		newTestTicket("p1", "p2"),
+		newTestTicket("s2"),
+		newTestTicket("s3"),
+		newTestTicket("s4"),
+		newTestTicket("s5"),
+		newTestTicket("s6"),
+		newTestTicket("s7"),
+	}
+
+	// act
+	matches := makeTestMatches(t, CrewMatchMaker{}, rules, tickets)
+
+	// assert
+	if assert.Len(t, matches, 1) && assert.Len(t, matches[0].Teams, 4) {
+		match := matches[0]
+		for _, team := range match.Teams {
+			assert.Len(t, team.UserIDs, 2)
+		}
+		assert.Equal(t, teamOf(match, "p1"), teamOf(match, "p2"))
+		assert.Len(t, match.Tickets, 7)
+		assert.Equal(t, []interface{}{[]interface{}{0, 1}, []interface{}{2, 3}}, match.MatchAttributes[alliancesAttribute])
+	}
+}
+
+func TestCrewMatchMakerShipsOnly(t *testing.T) {
+	// prepare
+	rules := GameRules{CrewType: "solo", ShipCountMin: 2, ShipCountMax: 2}
+	ranked := newTestTicket("r1")
+	ranked.MatchPool = "ranked"
+	tickets := []matchmaker.Ticket{newTestTicket("a1"), ranked, newTestTicket("b1"), newTestTicket("c1")}
+
+	// act
+	matches := makeTestMatches(t, CrewMatchMaker{}, rules, tickets)
+
+	// assert
+	if assert.Len(t, matches, 1) {
+		assert.Equal(t, []matchmaker.Team{{UserIDs: []player.ID{"a1"}}, {UserIDs: []player.ID{"b1"}}}, matches[0].Teams)
+		assert.Equal(t, []interface{}{[]interface{}{0}, []interface{}{1}}, matches[0].MatchAttributes[alliancesAttribute])
+	}
+}
+
+func TestCrewMatchMakerValidateTicket(t *testing.T) {
+	// prepare
+	rules := GameRules{CrewType: "sloop", ShipCountMax: 2}
+
+	// act
+	ok, err := CrewMatchMaker{}.ValidateTicket(newTestTicket("a1", "a2"), rules)
+	tooBig, tooBigErr := CrewMatchMaker{}.ValidateTicket(newTestTicket("a1", "a2", "a3"), rules)
+
+	// assert
+	assert.True(t, ok)
+	assert.Nil(t, err)
+	assert.False(t, tooBig)
+	assert.Equal(t, codes.InvalidArgument, status.Code(tooBigErr))
+}
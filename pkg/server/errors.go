@@ -0,0 +1,87 @@
+// Copyright (c) 2023 AccelByte Inc. All Rights Reserved.
+// This is licensed software from AccelByte Inc, for limitations
+// and restrictions contact your company contract manager.
+
+package server
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// errorDomain is the domain of the ErrorInfo details this server attaches to its errors
+const errorDomain = "matchmaking-function"
+
+// Reasons carried in the ErrorInfo details of authentication and authorization errors
+const (
+	ReasonTokenMissing      = "TOKEN_MISSING"
+	ReasonTokenInvalid      = "TOKEN_INVALID"
+	ReasonPermissionDenied  = "PERMISSION_DENIED"
+	ReasonNamespaceMismatch = "NAMESPACE_MISMATCH"
+)
+
+// insufficientPermissions is the error text of the SDK token validator when the token lacks the required permission
+const insufficientPermissions = "insufficient permissions"
+
+// errInsufficientPermissions is the permission failure of LocalTokenValidator, wrapped when it has more to say
+var errInsufficientPermissions = errors.New(insufficientPermissions)
+
+// statusWithDetails returns the error of a status with code and message, carrying the details when they can be encoded
+func statusWithDetails(code codes.Code, message string, details ...proto.Message) error {
+	st := status.New(code, message)
+	if withDetails, err := st.WithDetails(details...); err == nil {
+		return withDetails.Err()
+	}
+	return st.Err()
+}
+
+// unauthenticated is returned when the caller's token is missing or cannot be verified
+func unauthenticated(reason string, format string, args ...interface{}) error {
+	return statusWithDetails(codes.Unauthenticated, fmt.Sprintf(format, args...),
+		&errdetails.ErrorInfo{Reason: reason, Domain: errorDomain})
+}
+
+// permissionDenied is returned when the caller's token is valid but does not allow the call
+func permissionDenied(reason string, metadata map[string]string, format string, args ...interface{}) error {
+	return statusWithDetails(codes.PermissionDenied, fmt.Sprintf(format, args...),
+		&errdetails.ErrorInfo{Reason: reason, Domain: errorDomain, Metadata: metadata})
+}
+
+// namespaceMismatch is returned for a ticket of another namespace than the caller's
+func namespaceMismatch(ticketNamespace string, callerNamespace string) error {
+	return permissionDenied(ReasonNamespaceMismatch,
+		map[string]string{"ticketNamespace": ticketNamespace, "callerNamespace": callerNamespace},
+		"ticket namespace %q does not match the caller namespace %q", ticketNamespace, callerNamespace)
+}
+
+// authError maps an error of the token validator to Unauthenticated, or PermissionDenied for a missing permission
+func authError(err error, permission string) error {
+	if errors.Is(err, errInsufficientPermissions) || err.Error() == insufficientPermissions {
+		return permissionDenied(ReasonPermissionDenied, map[string]string{"permission": permission},
+			"token lacks the required permission %s", permission)
+	}
+	return unauthenticated(ReasonTokenInvalid, "invalid token: %s", err)
+}
+
+// invalidArgument is returned for a bad request, naming the field of the subject (e.g. rules or ticket) at fault
+func invalidArgument(subject string, field string, format string, args ...interface{}) error {
+	description := fmt.Sprintf(format, args...)
+	message := fmt.Sprintf("invalid %s: %s", subject, description)
+	if field != "" {
+		message = fmt.Sprintf("invalid %s: %s %s", subject, field, description)
+	}
+	return statusWithDetails(codes.InvalidArgument, message,
+		&errdetails.BadRequest{FieldViolations: []*errdetails.BadRequest_FieldViolation{{Field: field, Description: description}}})
+}
+
+// failedPrecondition is returned when a stream does not open the way the protocol requires
+func failedPrecondition(subject string, format string, args ...interface{}) error {
+	description := fmt.Sprintf(format, args...)
+	return statusWithDetails(codes.FailedPrecondition, description,
+		&errdetails.PreconditionFailure{Violations: []*errdetails.PreconditionFailure_Violation{{Type: "STREAM", Subject: subject, Description: description}}})
+}
@@ -0,0 +1,118 @@
+// Copyright (c) 2023 AccelByte Inc. All Rights Reserved.
+// This is licensed software from AccelByte Inc, for limitations
+// and restrictions contact your company contract manager.
+
+package server
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	matchfunctiongrpc "matchmaking-function-grpc-plugin-server-go/pkg/pb"
+)
+
+func TestAuthError(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		code   codes.Code
+		reason string
+	}{
+		{name: "missing permission", err: errors.New("insufficient permissions"), code: codes.PermissionDenied, reason: ReasonPermissionDenied},
+		{name: "revoked token", err: errors.New("token was revoked"), code: codes.Unauthenticated, reason: ReasonTokenInvalid},
+		{name: "malformed token", err: errors.New("square/go-jose: compact JWS format must have three parts"), code: codes.Unauthenticated, reason: ReasonTokenInvalid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// act
+			err := authError(tt.err, "NAMESPACE:accelbyte:MMV2GRPCSERVICE")
+
+			// assert
+			st := status.Convert(err)
+			assert.Equal(t, tt.code, st.Code())
+			if assert.Len(t, st.Details(), 1) {
+				info, ok := st.Details()[0].(*errdetails.ErrorInfo)
+				if assert.True(t, ok) {
+					assert.Equal(t, tt.reason, info.Reason)
+					assert.Equal(t, errorDomain, info.Domain)
+				}
+			}
+		})
+	}
+}
+
+func TestBackfillMatchesWithoutParameters(t *testing.T) {
+	// prepare
+	server := MatchFunctionServer{MM: NewGameMatchmaker()}
+	stream := &fakeBackfillStream{
+		ctx: context.Background(),
+		requests: []*matchfunctiongrpc.BackfillMakeMatchesRequest{
+			{RequestType: &matchfunctiongrpc.BackfillMakeMatchesRequest_Ticket{
+				Ticket: matchfunctiongrpc.MatchfunctionTicketToProtoTicket(newTestTicket("a1")),
+			}},
+		},
+	}
+
+	// act
+	err := server.BackfillMatches(stream)
+
+	// assert
+	st := status.Convert(err)
+	assert.Equal(t, codes.FailedPrecondition, st.Code())
+	if assert.Len(t, st.Details(), 1) {
+		failure, ok := st.Details()[0].(*errdetails.PreconditionFailure)
+		if assert.True(t, ok) {
+			assert.Equal(t, "parameters", failure.Violations[0].Subject)
+		}
+	}
+}
+
+func TestBackfillMatchesUnexpectedMessage(t *testing.T) {
+	// prepare
+	server := MatchFunctionServer{MM: NewGameMatchmaker()}
+	stream := &fakeBackfillStream{
+		ctx: context.Background(),
+		requests: []*matchfunctiongrpc.BackfillMakeMatchesRequest{
+			{RequestType: &matchfunctiongrpc.BackfillMakeMatchesRequest_Parameters{
+				Parameters: &matchfunctiongrpc.BackfillMakeMatchesRequest_MakeMatchesParameters{
+					Rules: &matchfunctiongrpc.Rules{Json: `{"alliance":{"player_max_number":2}}`},
+				},
+			}},
+			{},
+		},
+	}
+
+	// act
+	err := server.BackfillMatches(stream)
+
+	// assert
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestValidateTicketTooManyPlayers(t *testing.T) {
+	// prepare
+	server := MatchFunctionServer{MM: NewGameMatchmaker()}
+	req := &matchfunctiongrpc.ValidateTicketRequest{
+		Ticket: matchfunctiongrpc.MatchfunctionTicketToProtoTicket(newTestTicket("a1", "a2", "a3")),
+		Rules:  &matchfunctiongrpc.Rules{Json: `{"alliance":{"player_max_number":2}}`},
+	}
+
+	// act
+	_, err := server.ValidateTicket(context.Background(), req)
+
+	// assert
+	st := status.Convert(err)
+	assert.Equal(t, codes.InvalidArgument, st.Code())
+	if assert.Len(t, st.Details(), 1) {
+		badRequest, ok := st.Details()[0].(*errdetails.BadRequest)
+		if assert.True(t, ok) {
+			assert.Equal(t, "players", badRequest.FieldViolations[0].Field)
+		}
+	}
+}
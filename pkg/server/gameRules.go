@@ -5,8 +5,28 @@
 package server
 
 type GameRules struct {
-	AllianceRule AllianceRule `json:"alliance" bson:"allianceRule"`
-	CrewType     string       `json:"crewType"`
+	MatchLogic      string          `json:"match_logic" bson:"matchLogic"`
+	AllianceRule    AllianceRule    `json:"alliance" bson:"allianceRule"`
+	SkillRule       SkillRule       `json:"skill" bson:"skillRule"`
+	RegionRule      RegionRule      `json:"region" bson:"regionRule"`
+	PriorityRule    PriorityRule    `json:"priority" bson:"priorityRule"`
+	PartyRule       PartyRule       `json:"party" bson:"partyRule"`
+	CompositionRule CompositionRule `json:"composition" bson:"compositionRule"`
+	AttributeRules  []AttributeRule `json:"attribute_rules" bson:"attributeRules"`
+	VersionRule     VersionRule     `json:"client_version" bson:"versionRule"`
+	StatCodes       []string        `json:"stat_codes" bson:"statCodes"`
+
+	// CrewType sets the players of a crew for CrewMatchMaker, which puts between ShipCountMin and ShipCountMax crews
+	// in a match and groups them into alliances by the AllianceRule. CrewSize overrides the size of the CrewType
+	CrewType     string `json:"crewType"`
+	CrewSize     int    `json:"crewSize" valid:"range(0|2147483647)"`
+	ShipCountMin int    `json:"shipCountMin" valid:"range(0|2147483647)"`
+	ShipCountMax int    `json:"shipCountMax" valid:"range(0|2147483647)"`
+
+	// Incremental makes GameMatchMaker send a match as soon as a ticket completes one with every team at
+	// PlayerMaxNumber, instead of waiting for the whole pool. The match is seeded with the newest ticket, or the
+	// oldest when the PriorityRule says so. The tickets left at the end of the stream are still matched in batch
+	Incremental bool `json:"incremental" bson:"incremental"`
 }
 
 type AllianceRule struct {
@@ -15,3 +35,108 @@ type AllianceRule struct {
 	PlayerMinNumber int `json:"player_min_number" valid:"range(0|2147483647)"`
 	PlayerMaxNumber int `json:"player_max_number" valid:"range(0|2147483647)"`
 }
+
+// statCodes returns the declared stat codes without duplicates, in the order they were declared
+func (r GameRules) statCodes() []string {
+	codes := []string{}
+	seen := map[string]bool{}
+	for _, code := range r.StatCodes {
+		if code == "" || seen[code] {
+			continue
+		}
+		seen[code] = true
+		codes = append(codes, code)
+	}
+	return codes
+}
+
+// SkillRule groups tickets whose skill, the average of the Attribute over the players of a ticket, lies within
+// MaxSpread. The Attribute may name one of the rule set's stat codes, whose values the platform adds to the players.
+// The spread a match accepts widens by SpreadPerSecond for every second its oldest ticket has waited,
+// up to MaxWidenedSpread when that is set. Tickets without the Attribute are left out of the matches
+type SkillRule struct {
+	Attribute        string  `json:"attribute"`
+	MaxSpread        float64 `json:"max_spread" valid:"range(0|2147483647)"`
+	SpreadPerSecond  float64 `json:"spread_per_second" valid:"range(0|2147483647)"`
+	MaxWidenedSpread float64 `json:"max_widened_spread" valid:"range(0|2147483647)"`
+}
+
+// RegionRule only groups tickets that share a region with a latency of at most MaxLatency. The limit grows by
+// ExpansionStep for every ExpansionIntervalSeconds the oldest ticket of a match has waited, up to
+// MaxExpandedLatency when that is set. A zero MaxLatency turns the rule off
+type RegionRule struct {
+	MaxLatency               int64 `json:"max_latency" valid:"range(0|2147483647)"`
+	ExpansionStep            int64 `json:"expansion_step" valid:"range(0|2147483647)"`
+	ExpansionIntervalSeconds int64 `json:"expansion_interval_seconds" valid:"range(0|2147483647)"`
+	MaxExpandedLatency       int64 `json:"max_expanded_latency" valid:"range(0|2147483647)"`
+}
+
+// PriorityRule decides which tickets seed matches first and how the rules relax for tickets that waited long. With
+// OldestFirst the oldest ticket seeds the next match instead of the biggest. A match is formed under every Relaxation
+// whose AfterSeconds its seed ticket has waited, the later steps applying on top of the earlier ones
+type PriorityRule struct {
+	OldestFirst bool         `json:"oldest_first"`
+	Relaxations []Relaxation `json:"relaxations"`
+}
+
+// Relaxation lowers the team minimums and widens the skill spread and the region latency of the matches seeded by a
+// ticket that has waited AfterSeconds. A zero field leaves its rule as it is
+type Relaxation struct {
+	AfterSeconds    int64   `json:"after_seconds" valid:"range(0|2147483647)"`
+	MinNumber       int     `json:"min_number" valid:"range(0|2147483647)"`
+	PlayerMinNumber int     `json:"player_min_number" valid:"range(0|2147483647)"`
+	MaxSpread       float64 `json:"max_spread" valid:"range(0|2147483647)"`
+	MaxLatency      int64   `json:"max_latency" valid:"range(0|2147483647)"`
+}
+
+// PartyRule limits how many parties of a size one team takes. The tickets of a party, sharing a PartySessionID or the
+// PartyID of a player, always land on the same team and count as a single party of all their players. The logics
+// matching tickets as they arrive hold the tickets of a party until the stream ends and the whole party is known
+type PartyRule struct {
+	Limits []PartyLimit `json:"limits"`
+}
+
+// PartyLimit allows at most MaxPerTeam parties of Size players on one team
+type PartyLimit struct {
+	Size       int `json:"size" valid:"range(1|2147483647)"`
+	MaxPerTeam int `json:"max_per_team" valid:"range(0|2147483647)"`
+}
+
+// CompositionRule fills every team by role quotas. Each player takes one of the roles named by the Attribute of their
+// attributes, a single role or a list of acceptable ones, and a team only plays when every role has between Min and
+// Max players. The assigned roles are written to the "roles" match attribute, by player ID
+type CompositionRule struct {
+	Attribute string      `json:"attribute"`
+	Roles     []RoleQuota `json:"roles"`
+}
+
+// RoleQuota is the number of players of a role one team needs at least and takes at most
+type RoleQuota struct {
+	Role string `json:"role"`
+	Min  int    `json:"min" valid:"range(0|2147483647)"`
+	Max  int    `json:"max" valid:"range(1|2147483647)"`
+}
+
+// AttributeRule only lets tickets share a match when an attribute is compatible, read from the TicketAttributes or,
+// with the "player" Source, from the Attributes of every player. Of Type "equal" the values must all be the same, of
+// "intersect" the values, strings or lists of them, must have a string in common, and of "distance" the numbers must
+// be at most MaxDistance apart
+type AttributeRule struct {
+	Attribute   string  `json:"attribute"`
+	Type        string  `json:"type"`
+	Source      string  `json:"source"`
+	MaxDistance float64 `json:"max_distance" valid:"range(0|2147483647)"`
+}
+
+// VersionRule lets tickets of different client versions, read from the "client_version" ticket attribute, share a
+// match when both versions lie in one of the Compatible ranges. Tickets of the same version always may, and a match
+// runs the newest client version of its tickets
+type VersionRule struct {
+	Compatible []VersionRange `json:"compatible"`
+}
+
+// VersionRange holds the dotted client versions from Min to Max, both included
+type VersionRange struct {
+	Min string `json:"min"`
+	Max string `json:"max"`
+}
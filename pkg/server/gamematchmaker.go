@@ -5,17 +5,15 @@
 package server
 
 import (
-	"encoding/json"
-	"errors"
-	"fmt"
 	"github.com/sirupsen/logrus"
 	"golang.org/x/exp/maps"
 	"golang.org/x/exp/rand"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"matchmaking-function-grpc-plugin-server-go/pkg/matchmaker"
 	"matchmaking-function-grpc-plugin-server-go/pkg/player"
 	"math"
 	"sort"
-	"sync"
 	"time"
 )
 
@@ -24,30 +22,54 @@ func NewGameMatchmaker() MatchLogic {
 	return GameMatchMaker{}
 }
 
-type queue struct {
-	tickets []matchmaker.Ticket
-	lock    sync.Mutex
+// ticketFilter decides whether a ticket can join the tickets gathered for a match so far
+type ticketFilter interface {
+	fits(ticket matchmaker.Ticket) bool
+	add(ticket matchmaker.Ticket)
 }
 
-func (q *queue) push(ticket matchmaker.Ticket) {
-	q.lock.Lock()
-	defer q.lock.Unlock()
-	q.tickets = append(q.tickets, ticket)
+// triedRoots is the ticketFilter passing the tickets that have not seeded a game yet
+type triedRoots map[string]bool
+
+func (t triedRoots) fits(ticket matchmaker.Ticket) bool {
+	return !t[ticket.TicketID]
 }
 
-func (q *queue) pop() *matchmaker.Ticket {
-	if len(q.tickets) == 0 {
-		return nil
+func (t triedRoots) add(ticket matchmaker.Ticket) {
+	t[ticket.TicketID] = true
+}
+
+// matchFilters is the ticketFilter a ticket has to pass to join a match, it fits when it fits all of them
+type matchFilters []ticketFilter
+
+// newMatchFilters returns the filters of the game rules for a match seeded with the given ticket. The partition comes
+// first, so the tickets of other namespaces and match pools are counted as rejected for that reason alone
+func newMatchFilters(rules GameRules, seed matchmaker.Ticket, now time.Time) matchFilters {
+	filters := matchFilters{
+		newPartitionWindow(seed),
+		newSkillWindow(rules.SkillRule, seed, now),
+		newRegionWindow(rules.RegionRule, seed, now),
+		newVersionWindow(rules.VersionRule, seed),
 	}
-	q.lock.Lock()
-	defer q.lock.Unlock()
-	ticket := q.tickets[0]
-	q.tickets = q.tickets[1:]
-	return &ticket
+	for _, rule := range rules.AttributeRules {
+		filters = append(filters, newAttributeWindow(rule, seed))
+	}
+	return filters
 }
 
-func newQueue() *queue {
-	return &queue{tickets: make([]matchmaker.Ticket, 0)}
+func (f matchFilters) fits(ticket matchmaker.Ticket) bool {
+	for _, filter := range f {
+		if !filter.fits(ticket) {
+			return false
+		}
+	}
+	return true
+}
+
+func (f matchFilters) add(ticket matchmaker.Ticket) {
+	for _, filter := range f {
+		filter.add(ticket)
+	}
 }
 
 // ValidateTicket returns a bool if the match ticket is valid
@@ -55,20 +77,19 @@ func (g GameMatchMaker) ValidateTicket(matchTicket matchmaker.Ticket, matchRules
 	logrus.Info("GAME MATCHMAKER: validate ticket")
 	rules, ok := matchRules.(GameRules)
 	if !ok {
-		return false, errors.New("invalid rules type for game rules")
+		return false, status.Error(codes.Internal, "invalid rules type for game rules")
 	}
 
 	if len(matchTicket.Players) > rules.AllianceRule.PlayerMaxNumber {
-		return false, errors.New(fmt.Sprintf("too many players on the ticket, max is %d", rules.AllianceRule.PlayerMaxNumber))
-
+		return false, invalidArgument("ticket", "players", "has %d players, max is %d", len(matchTicket.Players), rules.AllianceRule.PlayerMaxNumber)
 	}
 
 	spawnLocation, ok := matchTicket.TicketAttributes["spawnLocation"].(float64)
 	if !ok {
-		return false, errors.New("spawnLocation must be a non-nil float64 value")
+		return false, invalidArgument("ticket", "ticket_attributes.spawnLocation", "must be a non-nil float64 value")
 	}
 	if spawnLocation == 0.0 {
-		return false, errors.New("spawnLocation cannot be nil value for a float")
+		return false, invalidArgument("ticket", "ticket_attributes.spawnLocation", "cannot be nil value for a float")
 	}
 
 	logrus.Info("Ticket Validation successful")
@@ -97,95 +118,364 @@ func (g GameMatchMaker) EnrichTicket(matchTicket matchmaker.Ticket, ruleSet inte
 
 // GetStatCodes returns the string slice of the stat codes in matchrules
 func (g GameMatchMaker) GetStatCodes(matchRules interface{}) []string {
-	logrus.Infof("GAME MATCHMAKER: stat codes: %s", []string{})
-	return []string{}
+	rules, ok := matchRules.(GameRules)
+	if !ok {
+		logrus.Error("invalid rules type for game rules")
+		return []string{}
+	}
+	codes := rules.statCodes()
+	logrus.Infof("GAME MATCHMAKER: stat codes: %s", codes)
+	return codes
 }
 
-// RulesFromJSON returns the ruleset from the Game rules
+// RulesFromJSON returns the ruleset from the Game rules, rejecting rules that are malformed, out of range, or
+// inconsistent with an InvalidArgument error naming the field
 func (g GameMatchMaker) RulesFromJSON(jsonRules string) (interface{}, error) {
 	var ruleSet GameRules
-	err := json.Unmarshal([]byte(jsonRules), &ruleSet)
-	if err != nil {
+	if err := decodeRules(jsonRules, &ruleSet); err != nil {
+		return nil, err
+	}
+	if err := ruleSet.Validate(); err != nil {
+		return nil, err
+	}
+	if err := ruleSet.validateTeams(); err != nil {
 		return nil, err
 	}
 	return ruleSet, nil
 }
 
-// MakeMatches iterates over all the match tickets and matches them based on the buildMatch function
-func (g GameMatchMaker) MakeMatches(ticketProvider TicketProvider, matchRules interface{}) <-chan matchmaker.Match {
-	logrus.Info("GAME MATCHMAKER: make matches")
+// MakeMatches iterates over all the match tickets and matches them based on the buildGame function, once the stream
+// has ended or, when the rules are incremental, as the tickets arrive
+func (g GameMatchMaker) MakeMatches(scope *Scope, ticketProvider TicketProvider, matchRules interface{}) <-chan matchmaker.Match {
+	scope.Log.Info("GAME MATCHMAKER: make matches")
 	results := make(chan matchmaker.Match)
 	rules, ok := matchRules.(GameRules)
 	if !ok {
-		logrus.Error("invalid rules type for game rules")
+		scope.Log.Error("invalid rules type for game rules")
+		close(results)
+		return results
+	}
+
+	if rules.Incremental {
+		go buildGameIncrementally(scope, ticketProvider.GetTickets(), results, rules)
 		return results
 	}
 
 	go func() {
 		var unmatchedTickets []matchmaker.Ticket
 		tickets := ticketProvider.GetTickets()
-		for ticket := range tickets {
-			unmatchedTickets = append(unmatchedTickets, ticket)
-			logrus.Infof("TICKET LENGTH: %d", len(unmatchedTickets))
+		for {
+			select {
+			case ticket, ok := <-tickets:
+				if !ok {
+					buildGame(scope, unmatchedTickets, results, rules)
+					return
+				}
+				unmatchedTickets = append(unmatchedTickets, ticket)
+				scope.Log.Infof("TICKET LENGTH: %d", len(unmatchedTickets))
+			case <-scope.Ctx.Done():
+				scope.Log.Info("GAME MATCHMAKER: CTX Done triggered")
+				close(results)
+				return
+			}
 		}
-		go buildGame(unmatchedTickets, results, rules)
 	}()
 
 	return results
 }
 
-func buildGame(unmatchedTickets []matchmaker.Ticket, results chan matchmaker.Match, gameRules GameRules) {
+// BackfillMatches tops up the teams of the partial matches with tickets from the pool up to the alliance rule limits
+func (g GameMatchMaker) BackfillMatches(scope *Scope, ticketProvider TicketProvider, matchRules interface{}) <-chan matchmaker.BackfillProposal {
+	scope.Log.Info("GAME MATCHMAKER: backfill matches")
+	results := make(chan matchmaker.BackfillProposal)
+	rules, ok := matchRules.(GameRules)
+	if !ok {
+		scope.Log.Error("invalid rules type for game rules")
+		close(results)
+		return results
+	}
+
+	go func() {
+		defer close(results)
+		pool, backfillTickets := collectBackfillPool(scope, ticketProvider)
+		buildBackfill(scope, backfillTickets, pool, results, rules)
+	}()
+
+	return results
+}
+
+// buildGame matches the tickets in batch, sending every game formGames finds to the results
+func buildGame(scope *Scope, unmatchedTickets []matchmaker.Ticket, results chan matchmaker.Match, gameRules GameRules) {
 	defer close(results)
-	logrus.Info("BUILD GAME")
+	formGames(scope, unmatchedTickets, gameRules, func(match matchmaker.Match) bool {
+		return sendMatch(scope, results, match)
+	})
+}
+
+// formGames matches the tickets in batch, handing every game to send, which returns false to stop
+func formGames(scope *Scope, unmatchedTickets []matchmaker.Ticket, gameRules GameRules, send func(matchmaker.Match) bool) {
+	scope.Log.Info("BUILD GAME")
 	max := gameRules.AllianceRule.PlayerMaxNumber
-	min := gameRules.AllianceRule.PlayerMinNumber
+	unmatchedTickets, joined := joinParties(unmatchedTickets)
 	buckets := map[int]*queue{}
 	for _, ticket := range unmatchedTickets {
-		bucket, ok := buckets[len(ticket.Players)]
-		if !ok {
-			bucket = newQueue()
-			buckets[len(ticket.Players)] = bucket
-		}
-		bucket.push(ticket)
+		pushTicket(buckets, ticket)
 	}
+	tried := triedRoots{}
 
 	//start outer loop
 	for {
-		rootTicket := nextTicket(buckets, max)
+		var rootTicket *matchmaker.Ticket
+		if gameRules.PriorityRule.OldestFirst {
+			rootTicket = oldestTicket(buckets, max, tried)
+		} else {
+			rootTicket = nextTicket(buckets, max, tried)
+		}
 		if rootTicket == nil {
 			return
 		}
-		remainingPlayerCount := max - len(rootTicket.Players)
-		logrus.Infof("OUTTER LOOP REMAINING: %d", remainingPlayerCount)
-
-		matchedTickets := []matchmaker.Ticket{*rootTicket}
+		tried.add(*rootTicket)
+		now := time.Now()
+		match := formGame(scope, buckets, *rootTicket, gameRules.relaxedFor(*rootTicket, now), now, false)
+		if match == nil {
+			continue
+		}
+		match.Tickets = joined.expand(match.Tickets)
+		setQuality(match, gameRules, now)
+		if !send(*match) {
+			return
+		}
+	}
+}
 
-		//start inner loop
-		for {
-			if remainingPlayerCount == 0 {
-				break
+// buildGameIncrementally sends a game as soon as an arriving ticket completes one, then matches the rest in batch
+func buildGameIncrementally(scope *Scope, tickets chan matchmaker.Ticket, results chan matchmaker.Match, gameRules GameRules) {
+	buckets := map[int]*queue{}
+	var partyTickets []matchmaker.Ticket
+	for {
+		select {
+		case ticket, ok := <-tickets:
+			if !ok {
+				buildGame(scope, append(drainBuckets(buckets), partyTickets...), results, gameRules)
+				return
 			}
-			otherTicket := nextTicket(buckets, remainingPlayerCount)
-			if otherTicket == nil {
-				if remainingPlayerCount <= min {
-					break
-				}
+			if len(partyKeys(ticket)) > 0 {
+				partyTickets = append(partyTickets, ticket)
+				continue
+			}
+			if len(ticket.Players) > gameRules.AllianceRule.PlayerMaxNumber {
+				scope.Log.Infof("GAME MATCHMAKER: ticket %s has more players than a team holds", ticket.TicketID)
+				pushTicket(buckets, ticket)
+				continue
+			}
+			now := time.Now()
+			var match *matchmaker.Match
+			if gameRules.PriorityRule.OldestFirst {
+				pushTicket(buckets, ticket)
+				match = formOldestGame(scope, buckets, gameRules, now)
+			} else {
+				match = formGame(scope, buckets, ticket, gameRules.relaxedFor(ticket, now), now, true)
+			}
+			if match == nil {
+				continue
+			}
+			setQuality(match, gameRules, now)
+			if !sendMatch(scope, results, *match) {
+				close(results)
 				return
 			}
-			matchedTickets = append(matchedTickets, *otherTicket)
-			remainingPlayerCount -= len(otherTicket.Players)
-			logrus.Infof("INNER LOOP REMIAINING: %d", remainingPlayerCount)
+		case <-scope.Ctx.Done():
+			scope.Log.Info("GAME MATCHMAKER: CTX Done triggered")
+			close(results)
+			return
 		}
+	}
+}
 
-		ffaTeam := mapPlayerIDs(matchedTickets)
-		match := matchmaker.Match{Tickets: matchedTickets,
-			Teams: []matchmaker.Team{{UserIDs: ffaTeam}}}
-		logrus.Infof("MATCH SENT TO RESULTS: %+v", match)
-		results <- match
+// formOldestGame tries the waiting tickets as roots of a game with full teams oldest first, each under the rules
+// relaxed for how long it waited, and returns the first game formed
+func formOldestGame(scope *Scope, buckets map[int]*queue, gameRules GameRules, now time.Time) *matchmaker.Match {
+	tried := triedRoots{}
+	for {
+		root := oldestTicket(buckets, gameRules.AllianceRule.PlayerMaxNumber, tried)
+		if root == nil {
+			return nil
+		}
+		tried.add(*root)
+		if match := formGame(scope, buckets, *root, gameRules.relaxedFor(*root, now), now, true); match != nil {
+			return match
+		}
 	}
 }
 
-func nextTicket(buckets map[int]*queue, maxPlayerCount int) *matchmaker.Ticket {
+// formGame fills teams around root with the tickets the rules allow, full ones when full is set, or returns nil
+func formGame(scope *Scope, buckets map[int]*queue, root matchmaker.Ticket, gameRules GameRules, now time.Time, full bool) *matchmaker.Match {
+	max := gameRules.AllianceRule.PlayerMaxNumber
+	min := gameRules.AllianceRule.PlayerMinNumber
+	teamMin, teamMax := teamCountRange(gameRules.AllianceRule)
+	countRejectedPairings(scope, buckets, root, gameRules.VersionRule)
+	filters := newMatchFilters(gameRules, root, now)
+	teams := make([][]matchmaker.Ticket, teamMax)
+	playerCounts := make([]int, teamMax)
+	teamFilters := make([]matchFilters, teamMax)
+	for team := range teamFilters {
+		teamFilters[team] = matchFilters{newPartyLimitWindow(gameRules.PartyRule), newRoleWindow(gameRules.CompositionRule, max)}
+	}
+	teams[0] = []matchmaker.Ticket{root}
+	playerCounts[0] = len(root.Players)
+	teamFilters[0].add(root)
+	scope.Log.Infof("OUTTER LOOP TEAMS: %d", teamMax)
+
+	//start inner loop, the emptiest team has the most room so when nothing fits there nothing fits anywhere
+	for {
+		team := emptiestTeam(playerCounts)
+		remainingPlayerCount := max - playerCounts[team]
+		if remainingPlayerCount == 0 {
+			break
+		}
+		otherTicket := nearestTicket(buckets, remainingPlayerCount, matchFilters{filters, teamFilters[team]}, skillDistance(gameRules.SkillRule, root))
+		if otherTicket == nil {
+			break
+		}
+		filters.add(*otherTicket)
+		teamFilters[team].add(*otherTicket)
+		teams[team] = append(teams[team], *otherTicket)
+		playerCounts[team] += len(otherTicket.Players)
+		scope.Log.Infof("INNER LOOP TEAM %d REMAINING: %d", team, max-playerCounts[team])
+	}
+
+	var fullTeams [][]matchmaker.Ticket
+	var leftoverTickets []matchmaker.Ticket
+	for team, teamTickets := range teams {
+		if playerCounts[team] < min || playerCounts[team] == 0 || (full && playerCounts[team] < max) {
+			leftoverTickets = append(leftoverTickets, teamTickets...)
+			continue
+		}
+		fullTeams = append(fullTeams, teamTickets)
+	}
+	if len(fullTeams) < teamMin || (full && len(fullTeams) < teamMax) {
+		for _, teamTickets := range teams {
+			for _, ticket := range teamTickets {
+				if ticket.TicketID != root.TicketID {
+					pushTicket(buckets, ticket)
+				}
+			}
+		}
+		fullTeams = packGame(buckets, root, gameRules, now, full)
+		if fullTeams == nil {
+			scope.Log.Infof("not enough players for %d teams of %d", teamMin, min)
+			return nil
+		}
+		scope.Log.Infof("PACKED %d TEAMS AROUND TICKET %s", len(fullTeams), root.TicketID)
+	} else {
+		for _, ticket := range leftoverTickets {
+			pushTicket(buckets, ticket)
+		}
+	}
+
+	var matchedTickets []matchmaker.Ticket
+	var matchTeams []matchmaker.Team
+	balanced := balanceTeams(fullTeams, gameRules.SkillRule, min, max)
+	if !gameRules.PartyRule.allows(balanced) {
+		balanced = fullTeams
+	}
+	roles, rolesOK := gameRules.CompositionRule.assignTeams(balanced)
+	if !rolesOK {
+		balanced = fullTeams
+		roles, rolesOK = gameRules.CompositionRule.assignTeams(balanced)
+	}
+	if splitsParty(balanced) || !rolesOK {
+		scope.Log.Infof("match around ticket %s would split a party or miss a role quota", root.TicketID)
+		for _, teamTickets := range balanced {
+			for _, ticket := range teamTickets {
+				pushTicket(buckets, ticket)
+			}
+		}
+		return nil
+	}
+	for _, teamTickets := range balanced {
+		matchedTickets = append(matchedTickets, teamTickets...)
+		matchTeams = append(matchTeams, matchmaker.Team{UserIDs: mapPlayerIDs(teamTickets)})
+	}
+
+	regions, _ := matchRegions(matchedTickets, gameRules.RegionRule, now)
+	match := &matchmaker.Match{Tickets: matchedTickets, Teams: matchTeams, RegionPreference: regions}
+	setServer(match)
+	if gameRules.CompositionRule.enabled() {
+		match.MatchAttributes = withRoles(match.MatchAttributes, roles)
+	}
+	return match
+}
+
+// sendMatch hands the match to the results channel, it returns false when the scope ended first
+func sendMatch(scope *Scope, results chan matchmaker.Match, match matchmaker.Match) bool {
+	scope.Log.Infof("MATCH SENT TO RESULTS: %+v", match)
+	select {
+	case results <- match:
+		observeWaitTimes(MatchLogicGame, match.Tickets, time.Now())
+		observeQuality(MatchLogicGame, match)
+		return true
+	case <-scope.Ctx.Done():
+		scope.Log.Info("GAME MATCHMAKER: CTX Done triggered")
+		return false
+	}
+}
+
+// teamCountRange returns how many teams a match needs at least and at most, a rule without team numbers is a single
+// free for all team
+func teamCountRange(rule AllianceRule) (int, int) {
+	teamMin, teamMax := rule.MinNumber, rule.MaxNumber
+	if teamMax < 1 {
+		teamMax = 1
+	}
+	if teamMin < 1 {
+		teamMin = 1
+	}
+	if teamMin > teamMax {
+		teamMin = teamMax
+	}
+	return teamMin, teamMax
+}
+
+// emptiestTeam returns the index of the team with the fewest players
+func emptiestTeam(playerCounts []int) int {
+	emptiest := 0
+	for team, count := range playerCounts {
+		if count < playerCounts[emptiest] {
+			emptiest = team
+		}
+	}
+	return emptiest
+}
+
+func pushTicket(buckets map[int]*queue, ticket matchmaker.Ticket) {
+	bucket, ok := buckets[len(ticket.Players)]
+	if !ok {
+		bucket = newQueue()
+		buckets[len(ticket.Players)] = bucket
+	}
+	bucket.push(ticket)
+}
+
+func nextTicket(buckets map[int]*queue, maxPlayerCount int, filter ticketFilter) *matchmaker.Ticket {
+	bucketKeys := maps.Keys(buckets)
+	sort.Ints(bucketKeys)
+
+	for i := len(bucketKeys) - 1; i >= 0; i-- {
+		if bucketKeys[i] > maxPlayerCount {
+			continue
+		}
+		ticket := buckets[bucketKeys[i]].pop(filter)
+		if ticket != nil {
+			return ticket
+		}
+	}
+	return nil
+}
+
+// nearestTicket takes the ticket fitting the filter closest in skill to the root of the match out of the biggest bucket
+// of at most maxPlayerCount players that has one
+func nearestTicket(buckets map[int]*queue, maxPlayerCount int, filter ticketFilter, distance func(matchmaker.Ticket) float64) *matchmaker.Ticket {
 	bucketKeys := maps.Keys(buckets)
 	sort.Ints(bucketKeys)
 
@@ -193,7 +483,7 @@ func nextTicket(buckets map[int]*queue, maxPlayerCount int) *matchmaker.Ticket {
 		if bucketKeys[i] > maxPlayerCount {
 			continue
 		}
-		ticket := buckets[bucketKeys[i]].pop()
+		ticket := buckets[bucketKeys[i]].popNearest(filter, distance)
 		if ticket != nil {
 			return ticket
 		}
@@ -201,6 +491,17 @@ func nextTicket(buckets map[int]*queue, maxPlayerCount int) *matchmaker.Ticket {
 	return nil
 }
 
+// drainBuckets empties the buckets, returning their tickets
+func drainBuckets(buckets map[int]*queue) []matchmaker.Ticket {
+	var tickets []matchmaker.Ticket
+	for _, bucket := range buckets {
+		for ticket := bucket.pop(nil); ticket != nil; ticket = bucket.pop(nil) {
+			tickets = append(tickets, *ticket)
+		}
+	}
+	return tickets
+}
+
 func mapPlayerIDs(tickets []matchmaker.Ticket) []player.ID {
 	playerIDs := []player.ID{}
 	for _, ticket := range tickets {
@@ -0,0 +1,382 @@
+// Copyright (c) 2023 AccelByte Inc. All Rights Reserved.
+// This is licensed software from AccelByte Inc, for limitations
+// and restrictions contact your company contract manager.
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"matchmaking-function-grpc-plugin-server-go/pkg/matchmaker"
+	"matchmaking-function-grpc-plugin-server-go/pkg/player"
+)
+
+func newTestTicket(playerIDs ...string) matchmaker.Ticket {
+	players := make([]player.PlayerData, 0, len(playerIDs))
+	for _, id := range playerIDs {
+		players = append(players, player.PlayerData{PlayerID: player.IDFromString(id)})
+	}
+
+	return matchmaker.Ticket{
+		TicketID:  GenerateUUID(),
+		MatchPool: "pool",
+		CreatedAt: time.Now(),
+		Players:   players,
+	}
+}
+
+func TestGameMatchMakerBackfillMatches(t *testing.T) {
+	// prepare
+	rules := GameRules{AllianceRule: AllianceRule{MinNumber: 2, MaxNumber: 2, PlayerMinNumber: 1, PlayerMaxNumber: 2}}
+	backfillTicket := matchmaker.BackfillTicket{
+		TicketID:  GenerateUUID(),
+		MatchPool: "pool",
+		PartialMatch: matchmaker.Match{
+			Teams: []matchmaker.Team{
+				{UserIDs: []player.ID{"a1", "a2"}},
+				{UserIDs: []player.ID{"b1"}},
+			},
+		},
+		MatchSessionID: "session",
+	}
+	ticketProvider := matchTicketProvider{
+		channelTickets:         make(chan matchmaker.Ticket),
+		channelBackfillTickets: make(chan matchmaker.BackfillTicket),
+	}
+
+	// act
+	proposals := GameMatchMaker{}.BackfillMatches(NewScope(context.Background(), ""), ticketProvider, rules)
+	go func() {
+		defer close(ticketProvider.channelTickets)
+		defer close(ticketProvider.channelBackfillTickets)
+		ticketProvider.channelBackfillTickets <- backfillTicket
+		ticketProvider.channelTickets <- newTestTicket("c1", "c2")
+		ticketProvider.channelTickets <- newTestTicket("d1")
+	}()
+	var got []matchmaker.BackfillProposal
+	for proposal := range proposals {
+		got = append(got, proposal)
+	}
+
+	// assert
+	if assert.Len(t, got, 1) {
+		proposal := got[0]
+		assert.Equal(t, backfillTicket.TicketID, proposal.BackfillTicketID)
+		assert.Equal(t, "pool", proposal.MatchPool)
+		assert.Equal(t, "session", proposal.MatchSessionID)
+		assert.NotEmpty(t, proposal.ProposalID)
+		assert.Len(t, proposal.AddedTickets, 1)
+		assert.Equal(t, []matchmaker.Team{
+			{UserIDs: []player.ID{"a1", "a2"}},
+			{UserIDs: []player.ID{"b1", "d1"}},
+		}, proposal.ProposedTeams)
+	}
+}
+
+func TestProposeBackfillFullMatch(t *testing.T) {
+	// prepare
+	backfillTicket := matchmaker.BackfillTicket{
+		TicketID: GenerateUUID(),
+		PartialMatch: matchmaker.Match{
+			Teams: []matchmaker.Team{{UserIDs: []player.ID{"a1", "a2"}}},
+		},
+	}
+	var pool []matchmaker.Ticket
+	for i := 0; i < 3; i++ {
+		pool = append(pool, newTestTicket(fmt.Sprintf("p%d", i)))
+	}
+
+	// act
+	proposal, remaining := proposeBackfill(NewScope(context.Background(), ""), backfillTicket, pool, GameRules{AllianceRule: AllianceRule{MinNumber: 1, MaxNumber: 1, PlayerMaxNumber: 2}})
+
+	// assert
+	assert.Nil(t, proposal)
+	assert.Len(t, remaining, 3)
+}
+
+func TestGameMatchMakerMakeMatchesCancelled(t *testing.T) {
+	// prepare
+	ctx, cancel := context.WithCancel(context.Background())
+	rules := GameRules{AllianceRule: AllianceRule{MinNumber: 1, MaxNumber: 1, PlayerMinNumber: 1, PlayerMaxNumber: 2}}
+	ticketProvider := matchTicketProvider{channelTickets: make(chan matchmaker.Ticket)}
+
+	// act
+	matches := GameMatchMaker{}.MakeMatches(NewScope(ctx, "trace"), ticketProvider, rules)
+	ticketProvider.channelTickets <- newTestTicket("a1")
+	cancel()
+
+	// assert
+	select {
+	case _, ok := <-matches:
+		assert.False(t, ok)
+	case <-time.After(time.Second):
+		assert.Fail(t, "results channel was not closed after the scope was cancelled")
+	}
+}
+
+func makeTestMatches(t *testing.T, matchLogic MatchLogic, rules interface{}, tickets []matchmaker.Ticket) []matchmaker.Match {
+	t.Helper()
+	ticketProvider := matchTicketProvider{channelTickets: make(chan matchmaker.Ticket)}
+	matches := matchLogic.MakeMatches(NewScope(context.Background(), ""), ticketProvider, rules)
+	go func() {
+		defer close(ticketProvider.channelTickets)
+		for _, ticket := range tickets {
+			ticketProvider.channelTickets <- ticket
+		}
+	}()
+	var got []matchmaker.Match
+	for match := range matches {
+		got = append(got, match)
+	}
+	return got
+}
+
+func TestGameMatchMakerTeams(t *testing.T) {
+	// prepare
+	rules := GameRules{AllianceRule: AllianceRule{MinNumber: 2, MaxNumber: 2, PlayerMinNumber: 4, PlayerMaxNumber: 4}}
+	tickets := []matchmaker.Ticket{
+		newTestTicket("a1", "a2", "a3"),
+		newTestTicket("b1", "b2", "b3"),
+		newTestTicket("c1"),
+		newTestTicket("d1"),
+		newTestTicket("e1", "e2"),
+	}
+
+	// act
+	matches := makeTestMatches(t, GameMatchMaker{}, rules, tickets)
+
+	// assert
+	if assert.Len(t, matches, 1) {
+		match := matches[0]
+		assert.Len(t, match.Tickets, 4)
+		if assert.Len(t, match.Teams, 2) {
+			assert.ElementsMatch(t, []player.ID{"a1", "a2", "a3", "c1"}, match.Teams[0].UserIDs)
+			assert.ElementsMatch(t, []player.ID{"b1", "b2", "b3", "d1"}, match.Teams[1].UserIDs)
+		}
+	}
+}
+
+func TestGameMatchMakerFreeForAll(t *testing.T) {
+	// prepare
+	rules := GameRules{AllianceRule: AllianceRule{PlayerMinNumber: 2, PlayerMaxNumber: 3}}
+	tickets := []matchmaker.Ticket{newTestTicket("a1"), newTestTicket("b1"), newTestTicket("c1")}
+
+	// act
+	matches := makeTestMatches(t, GameMatchMaker{}, rules, tickets)
+
+	// assert
+	if assert.Len(t, matches, 1) && assert.Len(t, matches[0].Teams, 1) {
+		assert.ElementsMatch(t, []player.ID{"a1", "b1", "c1"}, matches[0].Teams[0].UserIDs)
+	}
+}
+
+func newSkillTicket(playerID string, skill float64, createdAt time.Time) matchmaker.Ticket {
+	ticket := newTestTicket(playerID)
+	ticket.CreatedAt = createdAt
+	ticket.Players[0].Attributes = map[string]interface{}{"mmr": skill}
+	return ticket
+}
+
+func TestGameMatchMakerSkill(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name      string
+		skillRule SkillRule
+		tickets   []matchmaker.Ticket
+		want      [][]player.ID
+	}{
+		{
+			name:      "groups similar skill",
+			skillRule: SkillRule{Attribute: "mmr", MaxSpread: 5},
+			tickets: []matchmaker.Ticket{
+				newSkillTicket("p10", 10, now),
+				newSkillTicket("p50", 50, now),
+				newSkillTicket("p12", 12, now),
+				newSkillTicket("p52", 52, now),
+			},
+			want: [][]player.ID{{"p10", "p12"}, {"p50", "p52"}},
+		},
+		{
+			name:      "spread too narrow",
+			skillRule: SkillRule{Attribute: "mmr", MaxSpread: 5},
+			tickets:   []matchmaker.Ticket{newSkillTicket("p10", 10, now), newSkillTicket("p30", 30, now)},
+			want:      nil,
+		},
+		{
+			name:      "missing skill is left out",
+			skillRule: SkillRule{Attribute: "mmr", MaxSpread: 5},
+			tickets: []matchmaker.Ticket{
+				newSkillTicket("p2", 2, now),
+				newTestTicket("none1"),
+				newSkillTicket("p4", 4, now),
+				newTestTicket("none2"),
+			},
+			want: [][]player.ID{{"p2", "p4"}},
+		},
+		{
+			name:      "spread widens with ticket age",
+			skillRule: SkillRule{Attribute: "mmr", MaxSpread: 5, SpreadPerSecond: 2},
+			tickets:   []matchmaker.Ticket{newSkillTicket("p10", 10, now.Add(-10*time.Second)), newSkillTicket("p30", 30, now)},
+			want:      [][]player.ID{{"p10", "p30"}},
+		},
+		{
+			name:      "widening is capped",
+			skillRule: SkillRule{Attribute: "mmr", MaxSpread: 5, SpreadPerSecond: 2, MaxWidenedSpread: 10},
+			tickets:   []matchmaker.Ticket{newSkillTicket("p10", 10, now.Add(-10*time.Second)), newSkillTicket("p30", 30, now)},
+			want:      nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// prepare
+			rules := GameRules{AllianceRule: AllianceRule{PlayerMinNumber: 2, PlayerMaxNumber: 2}, SkillRule: tt.skillRule}
+
+			// act
+			matches := makeTestMatches(t, GameMatchMaker{}, rules, tt.tickets)
+
+			// assert
+			var got [][]player.ID
+			for _, match := range matches {
+				got = append(got, match.Teams[0].UserIDs)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestGameMatchMakerSkillBalancesTeams(t *testing.T) {
+	// prepare
+	now := time.Now()
+	rules := GameRules{
+		AllianceRule: AllianceRule{MinNumber: 2, MaxNumber: 2, PlayerMinNumber: 2, PlayerMaxNumber: 2},
+		SkillRule:    SkillRule{Attribute: "mmr", MaxSpread: 100},
+	}
+	tickets := []matchmaker.Ticket{
+		newSkillTicket("p10", 10, now),
+		newSkillTicket("p20", 20, now),
+		newSkillTicket("p30", 30, now),
+		newSkillTicket("p40", 40, now),
+	}
+
+	// act
+	matches := makeTestMatches(t, GameMatchMaker{}, rules, tickets)
+
+	// assert
+	if assert.Len(t, matches, 1) && assert.Len(t, matches[0].Teams, 2) {
+		assert.ElementsMatch(t, []player.ID{"p40", "p10"}, matches[0].Teams[0].UserIDs)
+		assert.ElementsMatch(t, []player.ID{"p30", "p20"}, matches[0].Teams[1].UserIDs)
+	}
+}
+
+func TestGameMatchMakerIncremental(t *testing.T) {
+	// prepare
+	rules := GameRules{
+		AllianceRule: AllianceRule{MinNumber: 2, MaxNumber: 2, PlayerMinNumber: 1, PlayerMaxNumber: 2},
+		Incremental:  true,
+	}
+	ticketProvider := matchTicketProvider{channelTickets: make(chan matchmaker.Ticket)}
+
+	// act
+	matches := GameMatchMaker{}.MakeMatches(NewScope(context.Background(), ""), ticketProvider, rules)
+	for _, ticket := range []matchmaker.Ticket{newTestTicket("a1", "a2"), newTestTicket("b1"), newTestTicket("c1")} {
+		ticketProvider.channelTickets <- ticket
+	}
+	var early matchmaker.Match
+	select {
+	case early = <-matches:
+	case <-time.After(time.Second):
+		t.Fatal("no match before the end of the stream")
+	}
+	ticketProvider.channelTickets <- newTestTicket("d1")
+	ticketProvider.channelTickets <- newTestTicket("e1")
+	close(ticketProvider.channelTickets)
+	var late []matchmaker.Match
+	for match := range matches {
+		late = append(late, match)
+	}
+
+	// assert
+	assert.Len(t, early.Teams, 2)
+	assert.ElementsMatch(t, []player.ID{"a1", "a2", "b1", "c1"}, append(early.Teams[0].UserIDs, early.Teams[1].UserIDs...))
+	if assert.Len(t, late, 1) {
+		assert.Len(t, late[0].Teams, 2)
+		assert.ElementsMatch(t, []player.ID{"d1", "e1"}, append(late[0].Teams[0].UserIDs, late[0].Teams[1].UserIDs...))
+	}
+}
+
+func TestGameMatchMakerPacksMixedPartySizes(t *testing.T) {
+	// prepare
+	rules := GameRules{AllianceRule: AllianceRule{MinNumber: 2, MaxNumber: 2, PlayerMinNumber: 4, PlayerMaxNumber: 4}}
+	tickets := []matchmaker.Ticket{
+		newTestTicket("a1", "a2", "a3"),
+		newTestTicket("b1", "b2", "b3"),
+		newTestTicket("c1", "c2"),
+		newTestTicket("d1", "d2"),
+		newTestTicket("e1", "e2"),
+		newTestTicket("f1", "f2"),
+	}
+
+	// act
+	matches := makeTestMatches(t, GameMatchMaker{}, rules, tickets)
+
+	// assert
+	if assert.Len(t, matches, 1) && assert.Len(t, matches[0].Teams, 2) {
+		assert.Len(t, matches[0].Tickets, 4)
+		assert.Len(t, matches[0].Teams[0].UserIDs, 4)
+		assert.Len(t, matches[0].Teams[1].UserIDs, 4)
+		assert.ElementsMatch(t, []player.ID{"c1", "c2", "d1", "d2", "e1", "e2", "f1", "f2"},
+			append(matches[0].Teams[0].UserIDs, matches[0].Teams[1].UserIDs...))
+	}
+}
+
+func TestGameMatchMakerContinuesAfterFailedRoot(t *testing.T) {
+	// prepare, the oldest ticket seeds first but shares no region with anyone
+	now := time.Now()
+	rules := GameRules{
+		AllianceRule: AllianceRule{PlayerMinNumber: 2, PlayerMaxNumber: 2},
+		RegionRule:   RegionRule{MaxLatency: 100},
+	}
+	tickets := []matchmaker.Ticket{
+		newLatencyTicket("x1", map[string]int64{"eu-west-1": 20}, now.Add(-3*time.Second)),
+		newLatencyTicket("b1", map[string]int64{"us-east-1": 20}, now.Add(-2*time.Second)),
+		newLatencyTicket("c1", map[string]int64{"us-east-1": 30}, now.Add(-time.Second)),
+	}
+
+	// act
+	matches := makeTestMatches(t, GameMatchMaker{}, rules, tickets)
+
+	// assert
+	if assert.Len(t, matches, 1) && assert.Len(t, matches[0].Teams, 1) {
+		assert.ElementsMatch(t, []player.ID{"b1", "c1"}, matches[0].Teams[0].UserIDs)
+	}
+}
+
+func TestSizeSearch(t *testing.T) {
+	tests := []struct {
+		name      string
+		available map[int]int
+		teamCount int
+		rootSize  int
+		min       int
+		max       int
+		want      [][]int
+	}{
+		{name: "pairs", available: map[int]int{3: 1, 2: 4}, teamCount: 2, rootSize: 2, min: 4, max: 4, want: [][]int{{2, 2}, {2, 2}}},
+		{name: "root of three", available: map[int]int{3: 1, 1: 2}, teamCount: 2, rootSize: 3, min: 4, max: 4, want: [][]int{{3, 1}, {3, 1}}},
+		{name: "below max", available: map[int]int{2: 2}, teamCount: 2, rootSize: 2, min: 2, max: 4, want: [][]int{{2, 2}, {2}}},
+		{name: "impossible", available: map[int]int{3: 3}, teamCount: 2, rootSize: 3, min: 4, max: 4, want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// act
+			got := newSizeSearch(tt.available, tt.min, tt.max, nil).teams(nil, tt.teamCount, tt.rootSize)
+
+			// assert
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -23,20 +23,27 @@ pool to match together.
 
 MakeMatches returns a channel to which it will post matches as they are found, and should close the channel when
 all matches are exhausted.  It should also watch for cancellation on the provided scope.Ctx, at which point it should
-stop looking for matches and close the result channel.
+stop looking for matches and close the result channel. Logging through scope.Log keeps the platform's trace id on
+every entry.
+
+BackfillMatches works like MakeMatches but for matches that are already in session and need more players: the
+TicketProvider serves the backfill tickets (partial matches) along with the tickets from the pool, and the returned
+channel receives a proposal for every partial match that could be topped up. It should close the channel once both
+of the provider's channels are exhausted.
 
 ValidateTicket should return false AND api.ErrInvalidRequest when a ticket is not allowed to be queued
 */
 type MatchLogic interface {
-	//TODO add in scope
-	MakeMatches(ticketProvider TicketProvider, matchRules interface{}) <-chan matchmaker.Match
+	MakeMatches(scope *Scope, ticketProvider TicketProvider, matchRules interface{}) <-chan matchmaker.Match
+	BackfillMatches(scope *Scope, ticketProvider TicketProvider, matchRules interface{}) <-chan matchmaker.BackfillProposal
 	RulesFromJSON(json string) (interface{}, error)
 	GetStatCodes(matchRules interface{}) []string
 	ValidateTicket(matchTicket matchmaker.Ticket, matchRules interface{}) (bool, error)
 	EnrichTicket(matchTicket matchmaker.Ticket, ruleSet interface{}) (ticket matchmaker.Ticket, err error)
 }
 
-// TicketProvider provides a mechanism for a match function to get tickets from the match pool it's trying to make matches for
+// TicketProvider provides a mechanism for a match function to get tickets from the match pool it's trying to make matches for.
+// GetBackfillTickets is only fed during BackfillMatches, otherwise it returns a closed channel
 type TicketProvider interface {
 	GetTickets() chan matchmaker.Ticket // I think we'd like to be able to query this, but not yet sure what that looks like
 	GetBackfillTickets() chan matchmaker.BackfillTicket
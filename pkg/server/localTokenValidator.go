@@ -0,0 +1,209 @@
+// Copyright (c) 2023 AccelByte Inc. All Rights Reserved.
+// This is licensed software from AccelByte Inc, for limitations
+// and restrictions contact your company contract manager.
+
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AccelByte/accelbyte-go-sdk/services-api/pkg/utils/auth/validator"
+	jose "github.com/AccelByte/go-jose"
+	"github.com/AccelByte/go-jose/jwt"
+	"github.com/sirupsen/logrus"
+)
+
+// LocalTokenValidator verifies access tokens without calling IAM: the signature against a JWKS read from a file or
+// URL, then the expiry, namespace, bans and the permissions carried in the token. Permissions only granted through
+// roles are not resolved, since that needs IAM. It is a validator.AuthTokenValidator, standing in for the SDK one
+type LocalTokenValidator struct {
+	jwksSource      string
+	refreshInterval time.Duration
+	httpClient      *http.Client
+
+	lock sync.RWMutex
+	keys jose.JSONWebKeySet
+}
+
+// NewLocalTokenValidator returns a validator reading its keys from jwksSource, an http(s) URL or a file path. Keys
+// served from a URL are refreshed every refreshInterval once initialized, a zero interval never refreshes
+func NewLocalTokenValidator(jwksSource string, refreshInterval time.Duration) *LocalTokenValidator {
+	return &LocalTokenValidator{
+		jwksSource:      jwksSource,
+		refreshInterval: refreshInterval,
+		httpClient:      &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Initialize loads the keys and starts refreshing them when they come from a URL
+func (v *LocalTokenValidator) Initialize() {
+	if err := v.LoadKeys(); err != nil {
+		logrus.Errorf("could not load the token keys: %s", err)
+	}
+	if v.refreshInterval > 0 && v.isURL() {
+		go func() {
+			for range time.Tick(v.refreshInterval) {
+				if err := v.LoadKeys(); err != nil {
+					logrus.Errorf("could not refresh the token keys: %s", err)
+				}
+			}
+		}()
+	}
+}
+
+// LoadKeys reads the key set from the source, replacing the keys in use
+func (v *LocalTokenValidator) LoadKeys() error {
+	var content []byte
+	var err error
+	if v.isURL() {
+		content, err = v.fetch()
+	} else {
+		content, err = os.ReadFile(v.jwksSource)
+	}
+	if err != nil {
+		return err
+	}
+
+	var keys jose.JSONWebKeySet
+	if err := json.Unmarshal(content, &keys); err != nil {
+		return fmt.Errorf("could not parse the key set: %w", err)
+	}
+	if len(keys.Keys) == 0 {
+		return errors.New("the key set has no keys")
+	}
+
+	v.lock.Lock()
+	defer v.lock.Unlock()
+	v.keys = keys
+	return nil
+}
+
+func (v *LocalTokenValidator) isURL() bool {
+	return strings.HasPrefix(v.jwksSource, "http://") || strings.HasPrefix(v.jwksSource, "https://")
+}
+
+func (v *LocalTokenValidator) fetch() ([]byte, error) {
+	resp, err := v.httpClient.Get(v.jwksSource)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("key set request returned %s", resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// Claims returns the claims of a token whose signature and expiry are valid
+func (v *LocalTokenValidator) Claims(token string) (*JWTClaims, error) {
+	parsed, err := jwt.ParseSigned(token)
+	if err != nil {
+		return nil, err
+	}
+	if len(parsed.Headers) == 0 {
+		return nil, errors.New("no headers found")
+	}
+	kid := parsed.Headers[0].KeyID
+	if kid == "" {
+		return nil, errors.New("'kid' header not found")
+	}
+
+	v.lock.RLock()
+	keys := v.keys.Key(kid)
+	v.lock.RUnlock()
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+
+	var claims JWTClaims
+	if err := parsed.Claims(keys[0].Key, &claims); err != nil {
+		return nil, err
+	}
+	if claims.Expiry == 0 {
+		return nil, errors.New("token has no expiry")
+	}
+	if err := claims.Validate(); err != nil {
+		return nil, err
+	}
+	return &claims, nil
+}
+
+// Validate checks the token, that it belongs to namespace and userId when they are given, that its user has no active
+// ban and that it grants permission
+func (v *LocalTokenValidator) Validate(token string, permission *validator.Permission, namespace *string, userId *string) error {
+	claims, err := v.Claims(token)
+	if err != nil {
+		return err
+	}
+
+	if namespace != nil && *namespace != "" && claims.Namespace != *namespace && claims.StudioNamespace != *namespace {
+		return fmt.Errorf("token belongs to namespace %q", claims.Namespace)
+	}
+	if userId != nil && *userId != "" && claims.Subject != *userId {
+		return errors.New("token belongs to another user")
+	}
+	now := time.Now().UTC()
+	for _, ban := range claims.Bans {
+		if ban.EndDate.After(now) {
+			return fmt.Errorf("%w: user is banned with %s", errInsufficientPermissions, ban.Ban)
+		}
+	}
+	if permission != nil && !claims.hasPermission(*permission, stringValue(namespace), claims.Subject) {
+		return errInsufficientPermissions
+	}
+	return nil
+}
+
+// hasPermission reports whether one of the token's permissions covers the required resource and every bit of its
+// action. The {namespace} and {userId} placeholders of both resources are filled in before comparing
+func (c *JWTClaims) hasPermission(required validator.Permission, namespace string, userID string) bool {
+	requiredResource := fillResource(required.Resource, namespace, userID)
+	for _, granted := range c.Permissions {
+		if granted.Action&required.Action != required.Action {
+			continue
+		}
+		if resourceMatches(fillResource(granted.Resource, namespace, userID), requiredResource) {
+			return true
+		}
+	}
+	return false
+}
+
+func fillResource(resource string, namespace string, userID string) string {
+	resource = strings.ReplaceAll(resource, namespacePlaceholder, namespace)
+	return strings.ReplaceAll(resource, "{userId}", userID)
+}
+
+// resourceMatches compares colon separated resources where a "*" segment of the granted resource matches any one
+// segment, and a trailing "*" segment matches all the remaining ones
+func resourceMatches(granted string, required string) bool {
+	grantedSegments := strings.Split(granted, ":")
+	requiredSegments := strings.Split(required, ":")
+	for i, segment := range grantedSegments {
+		if segment == "*" && i == len(grantedSegments)-1 {
+			return len(requiredSegments) >= len(grantedSegments)
+		}
+		if i >= len(requiredSegments) {
+			return false
+		}
+		if segment != "*" && segment != requiredSegments[i] {
+			return false
+		}
+	}
+	return len(grantedSegments) == len(requiredSegments)
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
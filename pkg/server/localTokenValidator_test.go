@@ -0,0 +1,205 @@
+// Copyright (c) 2023 AccelByte Inc. All Rights Reserved.
+// This is licensed software from AccelByte Inc, for limitations
+// and restrictions contact your company contract manager.
+
+package server
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/AccelByte/accelbyte-go-sdk/services-api/pkg/utils/auth/validator"
+	jose "github.com/AccelByte/go-jose"
+	"github.com/AccelByte/go-jose/jwt"
+	"github.com/stretchr/testify/assert"
+)
+
+type testKey struct {
+	kid     string
+	private *rsa.PrivateKey
+}
+
+func newTestKey(t *testing.T, kid string) testKey {
+	private, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return testKey{kid: kid, private: private}
+}
+
+func (k testKey) jwks(t *testing.T) []byte {
+	content, err := json.Marshal(jose.JSONWebKeySet{Keys: []jose.JSONWebKey{
+		{Key: &k.private.PublicKey, KeyID: k.kid, Algorithm: string(jose.RS256), Use: "sig"},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return content
+}
+
+func (k testKey) sign(t *testing.T, claims JWTClaims) string {
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.RS256, Key: k.private},
+		(&jose.SignerOptions{}).WithType("JWT").WithHeader("kid", k.kid))
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := jwt.Signed(signer).Claims(claims).CompactSerialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+func writeJWKS(t *testing.T, content []byte) string {
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, content, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func validTestClaims() JWTClaims {
+	return JWTClaims{
+		Namespace: "mygame",
+		ClientID:  "client",
+		Permissions: []Permission{
+			{Resource: "NAMESPACE:{namespace}:MMV2GRPCSERVICE", Action: 2},
+		},
+		Claims: jwt.Claims{
+			Subject: "user",
+			Expiry:  jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	}
+}
+
+func TestLocalTokenValidator(t *testing.T) {
+	key := newTestKey(t, "key-1")
+	otherKey := newTestKey(t, "key-1")
+	unknownKey := newTestKey(t, "key-2")
+	v := NewLocalTokenValidator(writeJWKS(t, key.jwks(t)), 0)
+	v.Initialize()
+
+	tests := []struct {
+		name       string
+		token      func() string
+		permission validator.Permission
+		err        bool
+		denied     bool
+	}{
+		{name: "valid", token: func() string { return key.sign(t, validTestClaims()) }},
+		{name: "not a jwt", token: func() string { return "abc" }, err: true},
+		{name: "unknown key", token: func() string { return unknownKey.sign(t, validTestClaims()) }, err: true},
+		{name: "bad signature", token: func() string { return otherKey.sign(t, validTestClaims()) }, err: true},
+		{name: "expired", token: func() string {
+			claims := validTestClaims()
+			claims.Expiry = jwt.NewNumericDate(time.Now().Add(-time.Hour))
+			return key.sign(t, claims)
+		}, err: true},
+		{name: "no expiry", token: func() string {
+			claims := validTestClaims()
+			claims.Expiry = 0
+			return key.sign(t, claims)
+		}, err: true},
+		{name: "other namespace", token: func() string {
+			claims := validTestClaims()
+			claims.Namespace = "othergame"
+			return key.sign(t, claims)
+		}, err: true},
+		{name: "studio namespace", token: func() string {
+			claims := validTestClaims()
+			claims.Namespace = "othergame"
+			claims.StudioNamespace = "mygame"
+			return key.sign(t, claims)
+		}},
+		{name: "active ban", token: func() string {
+			claims := validTestClaims()
+			claims.Bans = []JWTBan{{Ban: "MATCHMAKING", EndDate: time.Now().Add(time.Hour)}}
+			return key.sign(t, claims)
+		}, err: true, denied: true},
+		{name: "expired ban", token: func() string {
+			claims := validTestClaims()
+			claims.Bans = []JWTBan{{Ban: "MATCHMAKING", EndDate: time.Now().Add(-time.Hour)}}
+			return key.sign(t, claims)
+		}},
+		{name: "missing action", token: func() string { return key.sign(t, validTestClaims()) },
+			permission: validator.Permission{Resource: "NAMESPACE:mygame:MMV2GRPCSERVICE", Action: 4}, err: true, denied: true},
+		{name: "other resource", token: func() string { return key.sign(t, validTestClaims()) },
+			permission: validator.Permission{Resource: "NAMESPACE:mygame:OTHERSERVICE", Action: 2}, err: true, denied: true},
+		{name: "wildcard segment", token: func() string {
+			claims := validTestClaims()
+			claims.Permissions = []Permission{{Resource: "NAMESPACE:*:MMV2GRPCSERVICE", Action: 15}}
+			return key.sign(t, claims)
+		}},
+		{name: "trailing wildcard", token: func() string {
+			claims := validTestClaims()
+			claims.Permissions = []Permission{{Resource: "NAMESPACE:mygame:*", Action: 2}}
+			return key.sign(t, claims)
+		}},
+		{name: "wildcard too short", token: func() string {
+			claims := validTestClaims()
+			claims.Permissions = []Permission{{Resource: "NAMESPACE:*", Action: 2}}
+			return key.sign(t, claims)
+		}, permission: validator.Permission{Resource: "NAMESPACE", Action: 2}, err: true, denied: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// prepare
+			namespace := "mygame"
+			permission := tt.permission
+			if permission.Resource == "" {
+				permission = validator.Permission{Resource: "NAMESPACE:mygame:MMV2GRPCSERVICE", Action: 2}
+			}
+
+			// act
+			err := v.Validate(tt.token(), &permission, &namespace, nil)
+
+			// assert
+			if !tt.err {
+				assert.Nil(t, err)
+				return
+			}
+			assert.NotNil(t, err)
+			assert.Equal(t, tt.denied, errors.Is(err, errInsufficientPermissions))
+		})
+	}
+}
+
+func TestLocalTokenValidatorFromURL(t *testing.T) {
+	// prepare
+	key := newTestKey(t, "key-1")
+	jwks := key.jwks(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(jwks)
+	}))
+	defer server.Close()
+	v := NewLocalTokenValidator(server.URL, 0)
+
+	// act
+	loadErr := v.LoadKeys()
+	claims, err := v.Claims(key.sign(t, validTestClaims()))
+
+	// assert
+	assert.Nil(t, loadErr)
+	assert.Nil(t, err)
+	assert.Equal(t, "client", claims.ClientID)
+}
+
+func TestLocalTokenValidatorLoadKeysErrors(t *testing.T) {
+	// act
+	missingErr := NewLocalTokenValidator(filepath.Join(t.TempDir(), "missing.json"), 0).LoadKeys()
+	emptyErr := NewLocalTokenValidator(writeJWKS(t, []byte(`{"keys":[]}`)), 0).LoadKeys()
+	malformedErr := NewLocalTokenValidator(writeJWKS(t, []byte(`{"keys":`)), 0).LoadKeys()
+
+	// assert
+	assert.NotNil(t, missingErr)
+	assert.NotNil(t, emptyErr)
+	assert.NotNil(t, malformedErr)
+}
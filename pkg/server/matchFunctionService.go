@@ -6,25 +6,27 @@ package server
 
 import (
 	"context"
-	"errors"
 	"io"
 	"matchmaking-function-grpc-plugin-server-go/pkg/matchmaker"
 	"sync"
 
-	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/status"
 	matchfunctiongrpc "matchmaking-function-grpc-plugin-server-go/pkg/pb"
 )
 
-// MatchFunctionServer is for the handler (upper level of match logic)
+// MatchFunctionServer is for the handler (upper level of match logic). Calls are handled by the logic the Registry
+// picks for them, or by MM when there is no Registry
 type MatchFunctionServer struct {
 	matchfunctiongrpc.UnimplementedMatchFunctionServer
-	MM MatchLogic
+	MM       MatchLogic
+	Registry *Registry
 
 	unmatchedTickets []*matchmaker.Ticket
 }
 
 type matchTicketProvider struct {
-	channelTickets chan matchmaker.Ticket
+	channelTickets         chan matchmaker.Ticket
+	channelBackfillTickets chan matchmaker.BackfillTicket
 }
 
 func (m matchTicketProvider) GetTickets() chan matchmaker.Ticket {
@@ -32,45 +34,96 @@ func (m matchTicketProvider) GetTickets() chan matchmaker.Ticket {
 }
 
 func (m matchTicketProvider) GetBackfillTickets() chan matchmaker.BackfillTicket {
-	c := make(chan matchmaker.BackfillTicket)
-	close(c)
-	return c
+	if m.channelBackfillTickets == nil {
+		c := make(chan matchmaker.BackfillTicket)
+		close(c)
+		return c
+	}
+	return m.channelBackfillTickets
+}
+
+// matchLogic returns the logic handling a call with the given rules json and match pool
+func (m *MatchFunctionServer) matchLogic(rulesJSON string, matchPool string) (MatchLogic, error) {
+	if m.Registry == nil {
+		return m.MM, nil
+	}
+	return m.Registry.Lookup(rulesJSON, matchPool)
 }
 
+// GetStatCodes returns the stat codes of the rules. The request carries no match pool, so rules left to be routed by
+// match pool only have their stat codes read, without the checks of any one logic
 func (m *MatchFunctionServer) GetStatCodes(ctx context.Context, req *matchfunctiongrpc.GetStatCodesRequest) (*matchfunctiongrpc.StatCodesResponse, error) {
+	log := callerLog(ctx)
+	if m.Registry != nil && m.Registry.NeedsPool(req.GetRules().GetJson()) {
+		codes, err := statCodesFromJSON(req.GetRules().GetJson())
+		if err != nil {
+			log.Errorf("could not get stat codes from json: %s", err)
+			return nil, err
+		}
+		log.Infof("stat codes: %s", codes)
+		return &matchfunctiongrpc.StatCodesResponse{Codes: codes}, nil
+	}
 
-	rules, err := m.MM.RulesFromJSON(req.Rules.Json)
+	mm, err := m.matchLogic(req.GetRules().GetJson(), "")
 	if err != nil {
-		logrus.Errorf("could not get rules from json: %s", err)
+		log.Errorf("could not get match logic: %s", err)
 		return nil, err
 	}
 
-	codes := m.MM.GetStatCodes(rules)
-	logrus.Infof("stat codes: %s", codes)
+	rules, err := mm.RulesFromJSON(req.GetRules().GetJson())
+	if err != nil {
+		log.Errorf("could not get rules from json: %s", err)
+		return nil, err
+	}
+
+	codes := mm.GetStatCodes(rules)
+	log.Infof("stat codes: %s", codes)
 	return &matchfunctiongrpc.StatCodesResponse{Codes: codes}, nil
 }
 
 func (m *MatchFunctionServer) ValidateTicket(ctx context.Context, req *matchfunctiongrpc.ValidateTicketRequest) (*matchfunctiongrpc.ValidateTicketResponse, error) {
-	logrus.Info("SERVER: validate ticket")
+	log := callerLog(ctx)
+	log.Info("SERVER: validate ticket")
 
-	rules, err := m.MM.RulesFromJSON(req.Rules.Json)
+	matchTicket := matchfunctiongrpc.ProtoTicketToMatchfunctionTicket(req.Ticket)
+	if err := checkCallerNamespace(ctx, matchTicket.Namespace); err != nil {
+		log.Errorf("rejected ticket: %s", err)
+		return nil, err
+	}
+	mm, err := m.matchLogic(req.GetRules().GetJson(), matchTicket.MatchPool)
 	if err != nil {
-		logrus.Errorf("could not get rules from json: %s", err)
+		log.Errorf("could not get match logic: %s", err)
+		return nil, err
 	}
 
-	matchTicket := matchfunctiongrpc.ProtoTicketToMatchfunctionTicket(req.Ticket)
+	rules, err := mm.RulesFromJSON(req.GetRules().GetJson())
+	if err != nil {
+		log.Errorf("could not get rules from json: %s", err)
+		return nil, err
+	}
 
-	logrus.Infof("ValidateTicket in Namespace: %s", matchTicket.Namespace)
+	log.Infof("ValidateTicket in Namespace: %s", matchTicket.Namespace)
 
-	validTicket, err := m.MM.ValidateTicket(matchTicket, rules)
+	validTicket, err := mm.ValidateTicket(matchTicket, rules)
 	return &matchfunctiongrpc.ValidateTicketResponse{ValidTicket: validTicket}, err
 }
 
 func (m *MatchFunctionServer) EnrichTicket(ctx context.Context, req *matchfunctiongrpc.EnrichTicketRequest) (*matchfunctiongrpc.EnrichTicketResponse, error) {
-	logrus.Info("SERVER: enrich ticket")
+	log := callerLog(ctx)
+	log.Info("SERVER: enrich ticket")
 
 	matchTicket := matchfunctiongrpc.ProtoTicketToMatchfunctionTicket(req.Ticket)
-	enrichedTicket, err := m.MM.EnrichTicket(matchTicket, req.Rules)
+	if err := checkCallerNamespace(ctx, matchTicket.Namespace); err != nil {
+		log.Errorf("rejected ticket: %s", err)
+		return nil, err
+	}
+	mm, err := m.matchLogic(req.GetRules().GetJson(), matchTicket.MatchPool)
+	if err != nil {
+		log.Errorf("could not get match logic: %s", err)
+		return nil, err
+	}
+
+	enrichedTicket, err := mm.EnrichTicket(matchTicket, req.Rules)
 	if err != nil {
 		return nil, err
 	}
@@ -79,54 +132,103 @@ func (m *MatchFunctionServer) EnrichTicket(ctx context.Context, req *matchfuncti
 }
 
 func (m *MatchFunctionServer) MakeMatches(server matchfunctiongrpc.MatchFunction_MakeMatchesServer) error {
-	logrus.Info("SERVER: make matches")
+	log := callerLog(server.Context())
+	log.Info("SERVER: make matches")
 	matchesMade := 0
 	in, err := server.Recv()
 	if err != nil {
-		logrus.Errorf("error during stream Recv: %s", err)
+		log.Errorf("error during stream Recv: %s", err)
 		return err
 	}
 
 	mrpT, ok := in.GetRequestType().(*matchfunctiongrpc.MakeMatchesRequest_Parameters)
 	if !ok {
-		logrus.Error("not a MakeMatchesRequest_Parameters type")
-		return errors.New("expected parameters in the first message were not met")
+		log.Error("not a MakeMatchesRequest_Parameters type")
+		return failedPrecondition("parameters", "expected parameters in the first message were not met")
 	}
 
-	rules, err := m.MM.RulesFromJSON(mrpT.Parameters.Rules.Json)
+	// the match pool is only known from the tickets, so read one ahead when the pool picks the logic
+	rulesJSON := mrpT.Parameters.GetRules().GetJson()
+	var peeked *matchfunctiongrpc.MakeMatchesRequest
+	matchPool := ""
+	if m.Registry != nil && m.Registry.NeedsPool(rulesJSON) {
+		peeked, err = server.Recv()
+		if err == io.EOF {
+			log.Info("SERVER: make matches finished without tickets")
+			return nil
+		}
+		if err != nil {
+			log.Errorf("error during stream Recv: %s", err)
+			return err
+		}
+		matchPool = peeked.GetTicket().GetMatchPool()
+	}
+
+	mm, err := m.matchLogic(rulesJSON, matchPool)
+	if err != nil {
+		log.Errorf("could not get match logic: %s", err)
+		return err
+	}
+
+	rules, err := mm.RulesFromJSON(rulesJSON)
 	if err != nil {
-		logrus.Errorf("could not get rules from json: %s", err)
+		log.Errorf("could not get rules from json: %s", err)
 		return err
 	}
 
-	ticketProvider := matchTicketProvider{make(chan matchmaker.Ticket)}
-	resultChan := m.MM.MakeMatches(ticketProvider, rules)
+	ctx, cancel := context.WithCancel(server.Context())
+	defer cancel()
+	scope := NewScope(ctx, mrpT.Parameters.GetScope().GetAbTraceId())
+
+	ticketProvider := matchTicketProvider{channelTickets: make(chan matchmaker.Ticket)}
+	resultChan := mm.MakeMatches(scope, ticketProvider, rules)
 	wg := sync.WaitGroup{}
+	var recvErr, sendErr error
 
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
+		defer close(ticketProvider.channelTickets)
 		for {
-			req, err := server.Recv()
+			var req *matchfunctiongrpc.MakeMatchesRequest
+			var err error
+			if peeked != nil {
+				req, peeked = peeked, nil
+			} else {
+				req, err = server.Recv()
+			}
 			if err == io.EOF {
-				logrus.Infof("SERVER: %s", err)
-				close(ticketProvider.channelTickets)
+				scope.Log.Infof("SERVER: %s", err)
 				return
 			}
 			if err != nil {
-				logrus.Errorf("SERVER: recv %s", err)
+				scope.Log.Errorf("SERVER: recv %s", err)
+				recvErr = err
+				cancel()
 				return
 			}
 			t, ok := req.GetRequestType().(*matchfunctiongrpc.MakeMatchesRequest_Ticket)
 			if !ok {
-				logrus.Errorf("not a MakeMatchesRequest_Ticket: %s", t.Ticket)
+				scope.Log.Errorf("not a MakeMatchesRequest_Ticket: %T", req.GetRequestType())
+				recvErr = invalidArgument("request", "request_type", "expected a ticket after the parameters, got %T", req.GetRequestType())
+				cancel()
 				return
 			}
 
-			logrus.Info("SERVER: crafting a matchfunctions.Ticket")
+			scope.Log.Info("SERVER: crafting a matchfunctions.Ticket")
 			matchTicket := matchfunctiongrpc.ProtoTicketToMatchfunctionTicket(t.Ticket)
-			logrus.Infof("SERVER: writing match ticket: %+v", matchTicket)
-			ticketProvider.channelTickets <- matchTicket
+			if err := checkCallerNamespace(ctx, matchTicket.Namespace); err != nil {
+				scope.Log.Errorf("rejected ticket: %s", err)
+				recvErr = err
+				cancel()
+				return
+			}
+			scope.Log.Infof("SERVER: writing match ticket: %+v", matchTicket)
+			select {
+			case ticketProvider.channelTickets <- matchTicket:
+			case <-ctx.Done():
+				return
+			}
 		}
 	}()
 
@@ -134,11 +236,13 @@ func (m *MatchFunctionServer) MakeMatches(server matchfunctiongrpc.MatchFunction
 	go func() {
 		defer wg.Done()
 		for result := range resultChan {
-			logrus.Info("SERVER: crafting a MatchResponse")
+			scope.Log.Info("SERVER: crafting a MatchResponse")
 			resp := matchfunctiongrpc.MatchResponse{Match: matchfunctiongrpc.MatchfunctionMatchToProtoMatch(result)}
-			logrus.Infof("SERVER: match made and being sent back to the client: %+v", &resp)
+			scope.Log.Infof("SERVER: match made and being sent back to the client: %+v", &resp)
 			if err := server.Send(&resp); err != nil {
-				logrus.Errorf("error on server send: %s", err)
+				scope.Log.Errorf("error on server send: %s", err)
+				sendErr = err
+				cancel()
 				return
 			}
 			matchesMade++
@@ -146,37 +250,168 @@ func (m *MatchFunctionServer) MakeMatches(server matchfunctiongrpc.MatchFunction
 	}()
 	wg.Wait()
 
-	logrus.Infof("SERVER: make matches finished and %d matches were made", matchesMade)
-	return nil
-
+	scope.Log.Infof("SERVER: make matches finished and %d matches were made", matchesMade)
+	return streamError(server.Context(), recvErr, sendErr)
 }
 
 func (m *MatchFunctionServer) BackfillMatches(server matchfunctiongrpc.MatchFunction_BackfillMatchesServer) error {
-	ctx := server.Context()
-	defer ctx.Done()
-
-	for {
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		default:
-		}
+	log := callerLog(server.Context())
+	log.Info("SERVER: backfill matches")
+	proposalsMade := 0
+	in, err := server.Recv()
+	if err != nil {
+		log.Errorf("error during stream Recv: %s", err)
+		return err
+	}
+
+	bfpT, ok := in.GetRequestType().(*matchfunctiongrpc.BackfillMakeMatchesRequest_Parameters)
+	if !ok {
+		log.Error("not a BackfillMakeMatchesRequest_Parameters type")
+		return failedPrecondition("parameters", "expected parameters in the first message were not met")
+	}
 
-		in, err := server.Recv()
+	// the match pool is only known from the tickets, so read one ahead when the pool picks the logic
+	rulesJSON := bfpT.Parameters.GetRules().GetJson()
+	var peeked *matchfunctiongrpc.BackfillMakeMatchesRequest
+	matchPool := ""
+	if m.Registry != nil && m.Registry.NeedsPool(rulesJSON) {
+		peeked, err = server.Recv()
 		if err == io.EOF {
+			log.Info("SERVER: backfill matches finished without tickets")
 			return nil
 		}
 		if err != nil {
+			log.Errorf("error during stream Recv: %s", err)
 			return err
 		}
+		matchPool = peeked.GetBackfillTicket().GetMatchPool()
+		if matchPool == "" {
+			matchPool = peeked.GetTicket().GetMatchPool()
+		}
+	}
 
-		if backfillTicket := in.GetBackfillTicket(); backfillTicket != nil {
-			proposal := &matchfunctiongrpc.BackfillResponse{
-				BackfillProposal: &matchfunctiongrpc.BackfillProposal{},
+	mm, err := m.matchLogic(rulesJSON, matchPool)
+	if err != nil {
+		log.Errorf("could not get match logic: %s", err)
+		return err
+	}
+
+	rules, err := mm.RulesFromJSON(rulesJSON)
+	if err != nil {
+		log.Errorf("could not get rules from json: %s", err)
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(server.Context())
+	defer cancel()
+	scope := NewScope(ctx, bfpT.Parameters.GetScope().GetAbTraceId())
+
+	ticketProvider := matchTicketProvider{
+		channelTickets:         make(chan matchmaker.Ticket),
+		channelBackfillTickets: make(chan matchmaker.BackfillTicket),
+	}
+	resultChan := mm.BackfillMatches(scope, ticketProvider, rules)
+	wg := sync.WaitGroup{}
+	var recvErr, sendErr error
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer close(ticketProvider.channelTickets)
+		defer close(ticketProvider.channelBackfillTickets)
+		for {
+			var req *matchfunctiongrpc.BackfillMakeMatchesRequest
+			var err error
+			if peeked != nil {
+				req, peeked = peeked, nil
+			} else {
+				req, err = server.Recv()
 			}
-			if err := server.Send(proposal); err != nil {
-				return err
+			if err == io.EOF {
+				scope.Log.Infof("SERVER: %s", err)
+				return
 			}
+			if err != nil {
+				scope.Log.Errorf("SERVER: recv %s", err)
+				recvErr = err
+				cancel()
+				return
+			}
+
+			switch t := req.GetRequestType().(type) {
+			case *matchfunctiongrpc.BackfillMakeMatchesRequest_BackfillTicket:
+				backfillTicket := matchfunctiongrpc.ProtoBackfillTicketToMatchfunctionBackfillTicket(t.BackfillTicket)
+				for _, matched := range backfillTicket.PartialMatch.Tickets {
+					if err := checkCallerNamespace(ctx, matched.Namespace); err != nil {
+						scope.Log.Errorf("rejected backfill ticket %s: %s", backfillTicket.TicketID, err)
+						recvErr = err
+						cancel()
+						return
+					}
+				}
+				scope.Log.Infof("SERVER: writing backfill ticket: %+v", backfillTicket)
+				select {
+				case ticketProvider.channelBackfillTickets <- backfillTicket:
+				case <-ctx.Done():
+					return
+				}
+			case *matchfunctiongrpc.BackfillMakeMatchesRequest_Ticket:
+				matchTicket := matchfunctiongrpc.ProtoTicketToMatchfunctionTicket(t.Ticket)
+				if err := checkCallerNamespace(ctx, matchTicket.Namespace); err != nil {
+					scope.Log.Errorf("rejected ticket: %s", err)
+					recvErr = err
+					cancel()
+					return
+				}
+				scope.Log.Infof("SERVER: writing match ticket: %+v", matchTicket)
+				select {
+				case ticketProvider.channelTickets <- matchTicket:
+				case <-ctx.Done():
+					return
+				}
+			default:
+				scope.Log.Errorf("not a BackfillTicket or Ticket: %T", t)
+				recvErr = invalidArgument("request", "request_type", "expected a backfill ticket or a ticket after the parameters, got %T", t)
+				cancel()
+				return
+			}
+		}
+	}()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for result := range resultChan {
+			resp := matchfunctiongrpc.BackfillResponse{
+				BackfillProposal: matchfunctiongrpc.MatchfunctionBackfillProposalToProtoBackfillProposal(result),
+			}
+			scope.Log.Infof("SERVER: backfill proposal made and being sent back to the client: %+v", &resp)
+			if err := server.Send(&resp); err != nil {
+				scope.Log.Errorf("error on server send: %s", err)
+				sendErr = err
+				cancel()
+				return
+			}
+			proposalsMade++
 		}
+	}()
+	wg.Wait()
+
+	scope.Log.Infof("SERVER: backfill matches finished and %d proposals were made", proposalsMade)
+	return streamError(server.Context(), recvErr, sendErr)
+}
+
+// streamError returns the error a streaming call ends with: the first of a failed receive or send, else the status of
+// the stream's context
+func streamError(ctx context.Context, recvErr error, sendErr error) error {
+	if recvErr != nil {
+		return recvErr
 	}
+	if sendErr != nil {
+		return sendErr
+	}
+	if err := ctx.Err(); err != nil {
+		return status.FromContextError(err).Err()
+	}
+	return nil
 }
@@ -7,9 +7,14 @@ package server
 import (
 	"context"
 	"encoding/json"
+	"io"
+	"runtime"
+	"sync"
 	"testing"
+	"time"
 
 	"matchmaking-function-grpc-plugin-server-go/pkg/matchmaker"
+	"matchmaking-function-grpc-plugin-server-go/pkg/player"
 
 	matchfunctiongrpc "matchmaking-function-grpc-plugin-server-go/pkg/pb"
 
@@ -37,6 +42,27 @@ func TestGetStatCodes(t *testing.T) {
 	assert.NotNil(t, ok)
 }
 
+func TestGetStatCodesFromRules(t *testing.T) {
+	for _, matchMaker := range []MatchLogic{New(), NewGameMatchmaker()} {
+		// prepare
+		ctx := context.Background()
+		server := MatchFunctionServer{
+			UnimplementedMatchFunctionServer: matchfunctiongrpc.UnimplementedMatchFunctionServer{},
+			MM:                               matchMaker,
+		}
+		req := &matchfunctiongrpc.GetStatCodesRequest{
+			Rules: &matchfunctiongrpc.Rules{Json: `{"stat_codes":["mmr","kills","mmr"],"skill":{"attribute":"mmr"},"alliance":{"player_max_number":1}}`},
+		}
+
+		// act
+		resp, err := server.GetStatCodes(ctx, req)
+
+		// assert
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"mmr", "kills"}, resp.Codes)
+	}
+}
+
 func TestValidateTicket(t *testing.T) {
 	// prepare
 	s := grpc.NewServer()
@@ -70,6 +96,182 @@ func TestValidateTicket(t *testing.T) {
 	assert.Equal(t, ok.ValidTicket, true)
 }
 
+type fakeBackfillStream struct {
+	grpc.ServerStream
+	ctx       context.Context
+	requests  []*matchfunctiongrpc.BackfillMakeMatchesRequest
+	responses []*matchfunctiongrpc.BackfillResponse
+}
+
+func (f *fakeBackfillStream) Context() context.Context {
+	return f.ctx
+}
+
+func (f *fakeBackfillStream) Recv() (*matchfunctiongrpc.BackfillMakeMatchesRequest, error) {
+	if len(f.requests) == 0 {
+		return nil, io.EOF
+	}
+	req := f.requests[0]
+	f.requests = f.requests[1:]
+	return req, nil
+}
+
+func (f *fakeBackfillStream) Send(resp *matchfunctiongrpc.BackfillResponse) error {
+	f.responses = append(f.responses, resp)
+	return nil
+}
+
+func TestBackfillMatches(t *testing.T) {
+	// prepare
+	server := MatchFunctionServer{
+		UnimplementedMatchFunctionServer: matchfunctiongrpc.UnimplementedMatchFunctionServer{},
+		MM:                               NewGameMatchmaker(),
+	}
+	rules, _ := json.Marshal(GameRules{AllianceRule: AllianceRule{MinNumber: 1, MaxNumber: 1, PlayerMinNumber: 1, PlayerMaxNumber: 3}})
+	backfillTicket := matchmaker.BackfillTicket{
+		TicketID:       GenerateUUID(),
+		MatchPool:      "pool",
+		PartialMatch:   matchmaker.Match{Teams: []matchmaker.Team{{UserIDs: []player.ID{"a1"}}}},
+		MatchSessionID: "session",
+	}
+	ticket := newTestTicket("b1", "b2")
+	stream := &fakeBackfillStream{
+		ctx: context.Background(),
+		requests: []*matchfunctiongrpc.BackfillMakeMatchesRequest{
+			{RequestType: &matchfunctiongrpc.BackfillMakeMatchesRequest_Parameters{
+				Parameters: &matchfunctiongrpc.BackfillMakeMatchesRequest_MakeMatchesParameters{
+					Rules: &matchfunctiongrpc.Rules{Json: string(rules)},
+				},
+			}},
+			{RequestType: &matchfunctiongrpc.BackfillMakeMatchesRequest_BackfillTicket{
+				BackfillTicket: matchfunctiongrpc.MatchfunctionBackfillTicketToProtoBackfillTicket(backfillTicket),
+			}},
+			{RequestType: &matchfunctiongrpc.BackfillMakeMatchesRequest_Ticket{
+				Ticket: matchfunctiongrpc.MatchfunctionTicketToProtoTicket(ticket),
+			}},
+		},
+	}
+
+	// act
+	err := server.BackfillMatches(stream)
+
+	// assert
+	assert.Nil(t, err)
+	if assert.Len(t, stream.responses, 1) {
+		proposal := stream.responses[0].BackfillProposal
+		assert.Equal(t, backfillTicket.TicketID, proposal.BackfillTicketId)
+		assert.Equal(t, "session", proposal.MatchSessionId)
+		assert.Equal(t, "pool", proposal.MatchPool)
+		assert.NotEmpty(t, proposal.ProposalId)
+		assert.Equal(t, ticket.TicketID, proposal.AddedTickets[0].TicketId)
+		assert.Equal(t, []string{"a1", "b1", "b2"}, proposal.ProposedTeams[0].UserIds)
+	}
+}
+
+type fakeMakeMatchesStream struct {
+	grpc.ServerStream
+	ctx       context.Context
+	requests  chan *matchfunctiongrpc.MakeMatchesRequest
+	lock      sync.Mutex
+	responses []*matchfunctiongrpc.MatchResponse
+}
+
+func (f *fakeMakeMatchesStream) Context() context.Context {
+	return f.ctx
+}
+
+func (f *fakeMakeMatchesStream) Recv() (*matchfunctiongrpc.MakeMatchesRequest, error) {
+	select {
+	case req, ok := <-f.requests:
+		if !ok {
+			return nil, io.EOF
+		}
+		return req, nil
+	case <-f.ctx.Done():
+		return nil, f.ctx.Err()
+	}
+}
+
+func (f *fakeMakeMatchesStream) Send(resp *matchfunctiongrpc.MatchResponse) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.responses = append(f.responses, resp)
+	return nil
+}
+
+func (f *fakeMakeMatchesStream) matchesSent() int {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	return len(f.responses)
+}
+
+// assertNoGoroutineLeak polls instead of using assert.Eventually, which would count its own goroutine
+func assertNoGoroutineLeak(t *testing.T, goroutines int) {
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > goroutines && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.LessOrEqual(t, runtime.NumGoroutine(), goroutines, "goroutines leaked")
+}
+
+func TestMakeMatchesCancelled(t *testing.T) {
+	rules, _ := json.Marshal(GameRules{AllianceRule: AllianceRule{MinNumber: 1, MaxNumber: 1, PlayerMinNumber: 2, PlayerMaxNumber: 2}})
+	tests := []struct {
+		name        string
+		matchLogic  MatchLogic
+		wantMatches int
+	}{
+		{name: "match maker", matchLogic: New(), wantMatches: 1},
+		{name: "game match maker", matchLogic: NewGameMatchmaker(), wantMatches: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// prepare
+			goroutines := runtime.NumGoroutine()
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			server := MatchFunctionServer{
+				UnimplementedMatchFunctionServer: matchfunctiongrpc.UnimplementedMatchFunctionServer{},
+				MM:                               tt.matchLogic,
+			}
+			stream := &fakeMakeMatchesStream{ctx: ctx, requests: make(chan *matchfunctiongrpc.MakeMatchesRequest)}
+			done := make(chan error)
+
+			// act
+			go func() {
+				done <- server.MakeMatches(stream)
+			}()
+			stream.requests <- &matchfunctiongrpc.MakeMatchesRequest{
+				RequestType: &matchfunctiongrpc.MakeMatchesRequest_Parameters{
+					Parameters: &matchfunctiongrpc.MakeMatchesRequest_MakeMatchesParameters{
+						Scope: &matchfunctiongrpc.Scope{AbTraceId: "trace"},
+						Rules: &matchfunctiongrpc.Rules{Json: string(rules)},
+					},
+				},
+			}
+			for i := 0; i < 3; i++ {
+				stream.requests <- &matchfunctiongrpc.MakeMatchesRequest{
+					RequestType: &matchfunctiongrpc.MakeMatchesRequest_Ticket{
+						Ticket: matchfunctiongrpc.MatchfunctionTicketToProtoTicket(newTestTicket(GenerateUUID())),
+					},
+				}
+			}
+			assert.Eventually(t, func() bool { return stream.matchesSent() == tt.wantMatches }, time.Second, 10*time.Millisecond)
+			cancel()
+
+			// assert
+			select {
+			case err := <-done:
+				assert.ErrorIs(t, err, context.Canceled)
+			case <-time.After(time.Second):
+				assert.Fail(t, "MakeMatches did not stop after the stream was cancelled")
+			}
+			assert.Equal(t, tt.wantMatches, stream.matchesSent())
+			assertNoGoroutineLeak(t, goroutines)
+		})
+	}
+}
+
 // func TestMatch(t *testing.T) {
 // 	// prepare
 // 	s := grpc.NewServer()
@@ -5,8 +5,7 @@
 package server
 
 import (
-	"context"
-	"encoding/json"
+	"time"
 
 	pie_ "github.com/elliotchance/pie/v2"
 	"github.com/sirupsen/logrus"
@@ -41,16 +40,26 @@ func (b MatchMaker) EnrichTicket(matchTicket matchmaker.Ticket, ruleSet interfac
 	return matchTicket, nil
 }
 
+// GetStatCodes returns the stat codes declared by the rule set
 func (b MatchMaker) GetStatCodes(matchRules interface{}) []string {
-	logrus.Infof("MATCHMAKER: stat codes: %s", []string{})
-	return []string{}
+	rules, ok := matchRules.(GameRules)
+	if !ok {
+		logrus.Error("invalid rules type for game rules")
+		return []string{}
+	}
+	codes := rules.statCodes()
+	logrus.Infof("MATCHMAKER: stat codes: %s", codes)
+	return codes
 }
 
-// RulesFromJSON returns the ruleset from the Game rules
+// RulesFromJSON returns the ruleset from the Game rules. The simple logic pairs tickets without teams, so the alliance
+// rule may be left empty
 func (b MatchMaker) RulesFromJSON(jsonRules string) (interface{}, error) {
 	var ruleSet GameRules
-	err := json.Unmarshal([]byte(jsonRules), &ruleSet)
-	if err != nil {
+	if err := decodeRules(jsonRules, &ruleSet); err != nil {
+		return nil, err
+	}
+	if err := ruleSet.Validate(); err != nil {
 		return nil, err
 	}
 
@@ -58,25 +67,35 @@ func (b MatchMaker) RulesFromJSON(jsonRules string) (interface{}, error) {
 }
 
 // MakeMatches iterates over all the crew tickets and matches them based on the min/max of the game rules
-func (b MatchMaker) MakeMatches(ticketProvider TicketProvider, matchRules interface{}) <-chan matchmaker.Match {
-	logrus.Info("MATCHMAKER: make matches")
+func (b MatchMaker) MakeMatches(scope *Scope, ticketProvider TicketProvider, matchRules interface{}) <-chan matchmaker.Match {
+	scope.Log.Info("MATCHMAKER: make matches")
 	results := make(chan matchmaker.Match)
-	ctx := context.Background()
+	rules, ok := matchRules.(GameRules)
+	if !ok {
+		scope.Log.Error("invalid rules type for game rules")
+		close(results)
+		return results
+	}
 	go func() {
 		defer close(results)
-		var unmatchedTickets []matchmaker.Ticket
+		var unmatchedTickets, partyTickets []matchmaker.Ticket
 		nextTicket := ticketProvider.GetTickets()
 		for {
 			select {
 			case ticket, ok := <-nextTicket:
 				if !ok {
-					logrus.Info("MATCHMAKER: there are no tickets to create a match with")
+					scope.Log.Info("MATCHMAKER: there are no tickets to create a match with")
+					matchParties(scope, partyTickets, unmatchedTickets, results, rules)
 					return
 				}
-				logrus.Infof("MATCHMAKER: got a ticket: %s", ticket.TicketID)
-				unmatchedTickets = buildMatch(ticket, unmatchedTickets, results)
-			case <-ctx.Done():
-				logrus.Info("MATCHMAKER: CTX Done triggered")
+				scope.Log.Infof("MATCHMAKER: got a ticket: %s", ticket.TicketID)
+				if len(partyKeys(ticket)) > 0 {
+					partyTickets = append(partyTickets, ticket)
+					continue
+				}
+				unmatchedTickets = buildMatch(scope, ticket, unmatchedTickets, results, rules)
+			case <-scope.Ctx.Done():
+				scope.Log.Info("MATCHMAKER: CTX Done triggered")
 				return
 			}
 		}
@@ -84,27 +103,135 @@ func (b MatchMaker) MakeMatches(ticketProvider TicketProvider, matchRules interf
 	return results
 }
 
-// buildMatch is responsible for building matches from the slice of match tickets and feeding them to the match channel
-func buildMatch(ticket matchmaker.Ticket, unmatchedTickets []matchmaker.Ticket, results chan matchmaker.Match) []matchmaker.Ticket {
-	logrus.Info("MATCHMAKER: seeing if we have enough tickets to match")
-	unmatchedTickets = append(unmatchedTickets, ticket)
-	if len(unmatchedTickets) == 2 {
-		logrus.Info("MATCHMAKER: I have enough tickets to match!")
-		players := append(unmatchedTickets[0].Players, unmatchedTickets[1].Players...)
+// BackfillMatches tops up every partial match holding a single ticket with the next ticket from the pool in its
+// partition that asks for its server with a compatible client version
+func (b MatchMaker) BackfillMatches(scope *Scope, ticketProvider TicketProvider, matchRules interface{}) <-chan matchmaker.BackfillProposal {
+	scope.Log.Info("MATCHMAKER: backfill matches")
+	results := make(chan matchmaker.BackfillProposal)
+	rules, ok := matchRules.(GameRules)
+	if !ok {
+		scope.Log.Error("invalid rules type for game rules")
+		close(results)
+		return results
+	}
+	go func() {
+		defer close(results)
+		pool, backfillTickets := collectBackfillPool(scope, ticketProvider)
+		for _, backfillTicket := range backfillTickets {
+			if len(pool) == 0 {
+				scope.Log.Info("MATCHMAKER: there are no tickets left to backfill with")
+				return
+			}
+			if len(backfillTicket.PartialMatch.Tickets) >= 2 {
+				scope.Log.Infof("MATCHMAKER: backfill ticket %s is already full", backfillTicket.TicketID)
+				continue
+			}
+			next := -1
+			for i, ticket := range pool {
+				if inBackfillPartition(scope, backfillTicket, ticket) && backfillAllows(scope, rules.VersionRule, backfillTicket, backfillTicket.PartialMatch.Tickets, ticket) {
+					next = i
+					break
+				}
+			}
+			if next < 0 {
+				scope.Log.Infof("MATCHMAKER: no ticket asks for the server of backfill ticket %s", backfillTicket.TicketID)
+				continue
+			}
+			var userIDs []player.ID
+			for _, team := range backfillTicket.PartialMatch.Teams {
+				userIDs = append(userIDs, team.UserIDs...)
+			}
+			userIDs = append(userIDs, pie_.Map(pool[next].Players, player.ToID)...)
+			proposal := newBackfillProposal(backfillTicket, pool[next:next+1], []matchmaker.Team{{UserIDs: userIDs}})
+			pool = append(pool[:next:next], pool[next+1:]...)
+			scope.Log.Info("MATCHMAKER: sending backfill proposal to results channel")
+			select {
+			case results <- *proposal:
+			case <-scope.Ctx.Done():
+				return
+			}
+		}
+	}()
+	return results
+}
+
+// buildMatch pairs the ticket with the first unmatched ticket the rules allow and returns the tickets left unmatched
+func buildMatch(scope *Scope, ticket matchmaker.Ticket, unmatchedTickets []matchmaker.Ticket, results chan matchmaker.Match, rules GameRules) []matchmaker.Ticket {
+	scope.Log.Info("MATCHMAKER: seeing if we have enough tickets to match")
+	for i, other := range unmatchedTickets {
+		if !samePartition(scope, other, ticket) {
+			scope.Log.Infof("MATCHMAKER: tickets %s and %s are in different namespaces, match pools or servers", other.TicketID, ticket.TicketID)
+			continue
+		}
+		pair := []matchmaker.Ticket{other, ticket}
+		if !versionsAllow(scope, rules.VersionRule, pair) {
+			scope.Log.Infof("MATCHMAKER: tickets %s and %s run incompatible client versions", other.TicketID, ticket.TicketID)
+			continue
+		}
+		now := time.Now()
+		regions, ok := matchRegions(pair, rules.relaxedFor(other, now).RegionRule, now)
+		if !ok {
+			scope.Log.Infof("MATCHMAKER: tickets %s and %s share no region", other.TicketID, ticket.TicketID)
+			continue
+		}
+		if len(regions) == 0 {
+			regions = []string{"any"}
+		}
+		if !attributesAllow(rules.AttributeRules, pair) {
+			scope.Log.Infof("MATCHMAKER: tickets %s and %s have incompatible attributes", other.TicketID, ticket.TicketID)
+			continue
+		}
+		roles, ok := rules.CompositionRule.assignTeams([][]matchmaker.Ticket{pair})
+		if !ok {
+			scope.Log.Infof("MATCHMAKER: tickets %s and %s miss a role quota", other.TicketID, ticket.TicketID)
+			continue
+		}
+
+		scope.Log.Info("MATCHMAKER: I have enough tickets to match!")
+		players := append(append([]player.PlayerData{}, other.Players...), ticket.Players...)
 		playerIDs := pie_.Map(players, player.ToID)
 		match := matchmaker.Match{
-			RegionPreference: []string{"any"},
-			Tickets:          make([]matchmaker.Ticket, 2),
+			RegionPreference: regions,
+			Tickets:          pair,
 			Teams: []matchmaker.Team{
 				{UserIDs: playerIDs},
 			},
 		}
-		copy(match.Tickets, unmatchedTickets)
-		logrus.Info("MATCHMAKER: sending to results channel")
-		results <- match
-		logrus.Info("MATCHMAKER: resetting unmatched tickets")
-		unmatchedTickets = nil
+		setServer(&match)
+		setQuality(&match, rules, now)
+		if rules.CompositionRule.enabled() {
+			match.MatchAttributes = withRoles(match.MatchAttributes, roles)
+		}
+		scope.Log.Info("MATCHMAKER: sending to results channel")
+		select {
+		case results <- match:
+			observeWaitTimes(MatchLogicSimple, pair, time.Now())
+			observeQuality(MatchLogicSimple, match)
+		case <-scope.Ctx.Done():
+			return unmatchedTickets
+		}
+		scope.Log.Info("MATCHMAKER: removing matched ticket from unmatched tickets")
+		return append(unmatchedTickets[:i:i], unmatchedTickets[i+1:]...)
+	}
+	scope.Log.Info("MATCHMAKER: not enough tickets to build a match")
+	if rules.PriorityRule.OldestFirst {
+		return insertByAge(unmatchedTickets, ticket)
+	}
+	return append(unmatchedTickets, ticket)
+}
+
+// matchParties pairs the tickets of the parties once the stream has ended, never splitting a party across matches
+func matchParties(scope *Scope, partyTickets []matchmaker.Ticket, unmatchedTickets []matchmaker.Ticket, results chan matchmaker.Match, rules GameRules) {
+	joined, members := joinParties(partyTickets)
+	for _, ticket := range joined {
+		party, ok := members[ticket.TicketID]
+		switch {
+		case !ok:
+			unmatchedTickets = buildMatch(scope, ticket, unmatchedTickets, results, rules)
+		case len(party) == 2:
+			buildMatch(scope, party[1], party[:1], results, rules)
+		default:
+			scope.Log.Infof("MATCHMAKER: the party of ticket %s has %d tickets, more than a match holds", ticket.TicketID, len(party))
+		}
 	}
-	logrus.Info("MATCHMAKER: not enough tickets to build a match")
-	return unmatchedTickets
 }
@@ -0,0 +1,77 @@
+// Copyright (c) 2023 AccelByte Inc. All Rights Reserved.
+// This is licensed software from AccelByte Inc, for limitations
+// and restrictions contact your company contract manager.
+
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"matchmaking-function-grpc-plugin-server-go/pkg/matchmaker"
+	"matchmaking-function-grpc-plugin-server-go/pkg/player"
+)
+
+func TestMatchMakerBackfillMatches(t *testing.T) {
+	// prepare
+	halfMatch := matchmaker.BackfillTicket{
+		TicketID: GenerateUUID(),
+		PartialMatch: matchmaker.Match{
+			Tickets: []matchmaker.Ticket{newTestTicket("a1")},
+			Teams:   []matchmaker.Team{{UserIDs: []player.ID{"a1"}}},
+		},
+	}
+	fullMatch := matchmaker.BackfillTicket{
+		TicketID: GenerateUUID(),
+		PartialMatch: matchmaker.Match{
+			Tickets: []matchmaker.Ticket{newTestTicket("b1"), newTestTicket("b2")},
+			Teams:   []matchmaker.Team{{UserIDs: []player.ID{"b1", "b2"}}},
+		},
+	}
+	ticket := newTestTicket("c1")
+	ticketProvider := matchTicketProvider{
+		channelTickets:         make(chan matchmaker.Ticket),
+		channelBackfillTickets: make(chan matchmaker.BackfillTicket),
+	}
+
+	// act
+	proposals := New().BackfillMatches(NewScope(context.Background(), ""), ticketProvider, GameRules{})
+	go func() {
+		defer close(ticketProvider.channelTickets)
+		defer close(ticketProvider.channelBackfillTickets)
+		ticketProvider.channelBackfillTickets <- fullMatch
+		ticketProvider.channelBackfillTickets <- halfMatch
+		ticketProvider.channelTickets <- ticket
+	}()
+	var got []matchmaker.BackfillProposal
+	for proposal := range proposals {
+		got = append(got, proposal)
+	}
+
+	// assert
+	if assert.Len(t, got, 1) {
+		assert.Equal(t, halfMatch.TicketID, got[0].BackfillTicketID)
+		assert.Equal(t, []matchmaker.Ticket{ticket}, got[0].AddedTickets)
+		assert.Equal(t, []matchmaker.Team{{UserIDs: []player.ID{"a1", "c1"}}}, got[0].ProposedTeams)
+	}
+}
+
+func TestMatchMakerInvalidRulesType(t *testing.T) {
+	// prepare
+	scope := NewScope(context.Background(), "")
+	ticketProvider := matchTicketProvider{
+		channelTickets:         make(chan matchmaker.Ticket),
+		channelBackfillTickets: make(chan matchmaker.BackfillTicket),
+	}
+
+	// act
+	matches := New().MakeMatches(scope, ticketProvider, "not rules")
+	proposals := New().BackfillMatches(scope, ticketProvider, "not rules")
+
+	// assert
+	_, matchOpen := <-matches
+	_, proposalOpen := <-proposals
+	assert.False(t, matchOpen)
+	assert.False(t, proposalOpen)
+}
@@ -0,0 +1,94 @@
+// Copyright (c) 2023 AccelByte Inc. All Rights Reserved.
+// This is licensed software from AccelByte Inc, for limitations
+// and restrictions contact your company contract manager.
+
+package server
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"matchmaking-function-grpc-plugin-server-go/pkg/matchmaker"
+)
+
+var rejectedPairings = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "match_function_rejected_pairings_total",
+	Help: "Ticket pairings refused because the tickets belong to another namespace, match pool or server, or run incompatible client versions, by reason. A pairing counts once per call.",
+}, []string{"reason"})
+
+var ticketWaitSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "match_function_ticket_wait_seconds",
+	Help:    "Time the matched tickets waited since they were created, by match logic.",
+	Buckets: prometheus.ExponentialBuckets(1, 2, 12),
+}, []string{"match_logic"})
+
+// matchQuality holds a histogram of every numeric field of the quality record, by match logic
+var matchQuality = map[string]*prometheus.HistogramVec{
+	qualitySkillSpread: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "match_function_match_skill_spread",
+		Help:    "Spread of the ticket skills within the sent matches, by match logic.",
+		Buckets: prometheus.ExponentialBuckets(1, 2, 12),
+	}, []string{"match_logic"}),
+	qualityTeamBalanceDelta: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "match_function_match_team_balance_delta",
+		Help:    "Difference between the best and worst team's average skill of the sent matches, by match logic.",
+		Buckets: prometheus.ExponentialBuckets(1, 2, 12),
+	}, []string{"match_logic"}),
+	qualityMaxLatency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "match_function_match_max_latency_ms",
+		Help:    "Highest ticket latency to the preferred region of the sent matches, by match logic.",
+		Buckets: prometheus.ExponentialBuckets(10, 2, 10),
+	}, []string{"match_logic"}),
+	qualityAvgLatency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "match_function_match_avg_latency_ms",
+		Help:    "Average ticket latency to the preferred region of the sent matches, by match logic.",
+		Buckets: prometheus.ExponentialBuckets(10, 2, 10),
+	}, []string{"match_logic"}),
+	qualityLongestWait: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "match_function_match_longest_wait_seconds",
+		Help:    "Time the oldest ticket of the sent matches waited, by match logic.",
+		Buckets: prometheus.ExponentialBuckets(1, 2, 12),
+	}, []string{"match_logic"}),
+	qualityRulesRelaxed: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "match_function_match_rules_relaxed",
+		Help:    "Number of rules relaxed to form the sent matches, by match logic.",
+		Buckets: prometheus.LinearBuckets(0, 1, 4),
+	}, []string{"match_logic"}),
+}
+
+// observeQuality records the quality record of a match sent by the named logic, a match without one is skipped
+func observeQuality(matchLogic string, match matchmaker.Match) {
+	quality, ok := match.MatchAttributes[qualityAttribute].(map[string]interface{})
+	if !ok {
+		return
+	}
+	for field, histogram := range matchQuality {
+		switch value := quality[field].(type) {
+		case float64:
+			histogram.WithLabelValues(matchLogic).Observe(value)
+		case []interface{}:
+			histogram.WithLabelValues(matchLogic).Observe(float64(len(value)))
+		}
+	}
+}
+
+// observeWaitTimes records how long the tickets of a match sent by the named logic waited
+func observeWaitTimes(matchLogic string, tickets []matchmaker.Ticket, now time.Time) {
+	for _, ticket := range tickets {
+		ticketWaitSeconds.WithLabelValues(matchLogic).Observe(now.Sub(ticket.CreatedAt).Seconds())
+	}
+}
+
+// Collectors returns the metrics of the match logics, to be registered with the server's prometheus registry
+func Collectors() []prometheus.Collector {
+	return []prometheus.Collector{
+		rejectedPairings,
+		ticketWaitSeconds,
+		matchQuality[qualitySkillSpread],
+		matchQuality[qualityTeamBalanceDelta],
+		matchQuality[qualityMaxLatency],
+		matchQuality[qualityAvgLatency],
+		matchQuality[qualityLongestWait],
+		matchQuality[qualityRulesRelaxed],
+	}
+}
@@ -0,0 +1,152 @@
+// Copyright (c) 2023 AccelByte Inc. All Rights Reserved.
+// This is licensed software from AccelByte Inc, for limitations
+// and restrictions contact your company contract manager.
+
+package server
+
+import (
+	"sort"
+	"time"
+
+	"golang.org/x/exp/maps"
+	"matchmaking-function-grpc-plugin-server-go/pkg/matchmaker"
+)
+
+// maxPackingSteps bounds the search for team compositions, so a big pool of mixed party sizes cannot stall a call
+const maxPackingSteps = 10000
+
+// packGame is the fallback of formGame for when filling the emptiest team first leaves teams short, e.g. two teams of
+// four out of parties of three and two. It searches the party sizes each team could take among the tickets fitting
+// root, then takes those tickets from the buckets. It returns nil with every ticket back in the buckets when no
+// composition works out
+func packGame(buckets map[int]*queue, root matchmaker.Ticket, gameRules GameRules, now time.Time, full bool) [][]matchmaker.Ticket {
+	playerMin, playerMax := gameRules.AllianceRule.PlayerMinNumber, gameRules.AllianceRule.PlayerMaxNumber
+	teamMin, teamMax := teamCountRange(gameRules.AllianceRule)
+	if full {
+		playerMin, teamMin = playerMax, teamMax
+	}
+
+	available := map[int]int{}
+	for size, bucket := range buckets {
+		available[size] = bucket.count(newMatchFilters(gameRules, root, now))
+	}
+	var sizes [][]int
+	for teamCount := teamMax; teamCount >= teamMin && sizes == nil; teamCount-- {
+		search := newSizeSearch(available, playerMin, playerMax, gameRules.PartyRule.limits())
+		sizes = search.teams(nil, teamCount, len(root.Players))
+	}
+	if sizes == nil {
+		pushTicket(buckets, root)
+		return nil
+	}
+
+	filters := newMatchFilters(gameRules, root, now)
+	teams := make([][]matchmaker.Ticket, len(sizes))
+	teams[0] = []matchmaker.Ticket{root}
+	for team, teamSizes := range sizes {
+		if team == 0 {
+			teamSizes = teamSizes[1:]
+		}
+		for _, size := range teamSizes {
+			ticket := buckets[size].popNearest(filters, skillDistance(gameRules.SkillRule, root))
+			if ticket == nil {
+				// the filters tighten as tickets join, so the counted tickets may not all fit together
+				for _, teamTickets := range teams {
+					for _, taken := range teamTickets {
+						pushTicket(buckets, taken)
+					}
+				}
+				return nil
+			}
+			filters.add(*ticket)
+			teams[team] = append(teams[team], *ticket)
+		}
+	}
+	return teams
+}
+
+// sizeSearch looks for the party sizes of the tickets each team takes, so every team holds between playerMin and
+// playerMax players without using more tickets of a size than are available, nor more parties of a size than limits
+// allow on one team
+type sizeSearch struct {
+	available map[int]int
+	limits    map[int]int
+	sizes     []int
+	playerMin int
+	playerMax int
+	steps     int
+}
+
+func newSizeSearch(available map[int]int, playerMin int, playerMax int, limits map[int]int) *sizeSearch {
+	sizes := maps.Keys(available)
+	sort.Sort(sort.Reverse(sort.IntSlice(sizes)))
+	if playerMin < 1 {
+		playerMin = 1
+	}
+	return &sizeSearch{
+		available: maps.Clone(available),
+		limits:    limits,
+		sizes:     sizes,
+		playerMin: playerMin,
+		playerMax: playerMax,
+	}
+}
+
+// teams completes the found teams up to teamCount, the first team starting with the root ticket of rootSize players.
+// Fuller teams are tried first
+func (s *sizeSearch) teams(found [][]int, teamCount int, rootSize int) [][]int {
+	if len(found) == teamCount {
+		return found
+	}
+	var team []int
+	players := 0
+	if len(found) == 0 {
+		team, players = []int{rootSize}, rootSize
+	}
+	for target := s.playerMax; target >= s.playerMin; target-- {
+		if result := s.team(found, teamCount, rootSize, team, players, target, 0); result != nil {
+			return result
+		}
+	}
+	return nil
+}
+
+// team adds tickets of the sizes from index i on until the team holds target players, sizes never increase within a
+// team so each combination is only tried once
+func (s *sizeSearch) team(found [][]int, teamCount int, rootSize int, team []int, players int, target int, i int) [][]int {
+	s.steps++
+	if s.steps > maxPackingSteps {
+		return nil
+	}
+	if players == target {
+		return s.teams(append(found[:len(found):len(found)], team), teamCount, rootSize)
+	}
+	for j := i; j < len(s.sizes); j++ {
+		size := s.sizes[j]
+		if s.available[size] == 0 || players+size > target || !s.allows(team, size) {
+			continue
+		}
+		s.available[size]--
+		result := s.team(found, teamCount, rootSize, append(team[:len(team):len(team)], size), players+size, target, j)
+		s.available[size]++
+		if result != nil {
+			return result
+		}
+	}
+	return nil
+}
+
+// allows reports whether the team may take one more party of size
+func (s *sizeSearch) allows(team []int, size int) bool {
+	limit, ok := s.limits[size]
+	if !ok {
+		return true
+	}
+	count := 0
+	for _, taken := range team {
+		if taken == size {
+			count++
+		}
+	}
+	return count < limit
+}
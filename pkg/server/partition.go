@@ -0,0 +1,82 @@
+// Copyright (c) 2023 AccelByte Inc. All Rights Reserved.
+// This is licensed software from AccelByte Inc, for limitations
+// and restrictions contact your company contract manager.
+
+package server
+
+import (
+	"matchmaking-function-grpc-plugin-server-go/pkg/matchmaker"
+)
+
+// serverNameAttribute is the ticket attribute naming the local DS the ticket's players want to play on
+const serverNameAttribute = "server_name"
+
+// partition is the namespace, match pool and requested server of a ticket, tickets of different partitions never
+// share a match
+type partition struct {
+	namespace  string
+	matchPool  string
+	serverName string
+}
+
+func ticketPartition(ticket matchmaker.Ticket) partition {
+	return partition{namespace: ticket.Namespace, matchPool: ticket.MatchPool, serverName: ticketServerName(ticket)}
+}
+
+// ticketServerName returns the local DS the ticket asks for, empty when it takes any server
+func ticketServerName(ticket matchmaker.Ticket) string {
+	name, _ := ticket.TicketAttributes[serverNameAttribute].(string)
+	return name
+}
+
+// partitionMismatch returns the reason the tickets may not share a match, empty when their partitions are the same
+func partitionMismatch(a matchmaker.Ticket, b matchmaker.Ticket) string {
+	pa, pb := ticketPartition(a), ticketPartition(b)
+	switch {
+	case pa.namespace != pb.namespace:
+		return "namespace"
+	case pa.matchPool != pb.matchPool:
+		return "match_pool"
+	case pa.serverName != pb.serverName:
+		return "server_name"
+	default:
+		return ""
+	}
+}
+
+// samePartition reports whether the tickets may share a match, counting the pairing as rejected when they may not.
+// Filters scanning the buckets use partitionMismatch
+func samePartition(scope *Scope, a matchmaker.Ticket, b matchmaker.Ticket) bool {
+	if reason := partitionMismatch(a, b); reason != "" {
+		scope.rejectPairing(reason, a.TicketID, b.TicketID)
+		return false
+	}
+	return true
+}
+
+// countRejectedPairings counts the tickets waiting in the buckets that cannot share a match with root for their
+// partition or client version
+func countRejectedPairings(scope *Scope, buckets map[int]*queue, root matchmaker.Ticket, rule VersionRule) {
+	for _, bucket := range buckets {
+		for _, ticket := range bucket.snapshot() {
+			if samePartition(scope, root, ticket) {
+				versionsAllow(scope, rule, []matchmaker.Ticket{root, ticket})
+			}
+		}
+	}
+}
+
+// partitionWindow is the ticketFilter keeping every ticket of a match in the partition of the ticket it was seeded with
+type partitionWindow struct {
+	seed matchmaker.Ticket
+}
+
+func newPartitionWindow(seed matchmaker.Ticket) *partitionWindow {
+	return &partitionWindow{seed: seed}
+}
+
+func (w *partitionWindow) fits(ticket matchmaker.Ticket) bool {
+	return partitionMismatch(w.seed, ticket) == ""
+}
+
+func (w *partitionWindow) add(matchmaker.Ticket) {}
@@ -0,0 +1,170 @@
+// Copyright (c) 2023 AccelByte Inc. All Rights Reserved.
+// This is licensed software from AccelByte Inc, for limitations
+// and restrictions contact your company contract manager.
+
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"matchmaking-function-grpc-plugin-server-go/pkg/matchmaker"
+	"matchmaking-function-grpc-plugin-server-go/pkg/player"
+)
+
+func newPartitionTicket(namespace string, matchPool string, playerID string) matchmaker.Ticket {
+	ticket := newTestTicket(playerID)
+	ticket.Namespace = namespace
+	ticket.MatchPool = matchPool
+	return ticket
+}
+
+// assertSinglePartition checks that every match holds the tickets of one namespace and match pool only
+func assertSinglePartition(t *testing.T, matches []matchmaker.Match) {
+	t.Helper()
+	for _, match := range matches {
+		for _, ticket := range match.Tickets {
+			assert.Equal(t, ticketPartition(match.Tickets[0]), ticketPartition(ticket))
+		}
+	}
+}
+
+func TestGameMatchMakerPartitions(t *testing.T) {
+	// prepare
+	rules := GameRules{AllianceRule: AllianceRule{PlayerMinNumber: 2, PlayerMaxNumber: 2}}
+	tickets := []matchmaker.Ticket{
+		newPartitionTicket("game-a", "pool", "a1"),
+		newPartitionTicket("game-b", "pool", "b1"),
+		newPartitionTicket("game-a", "ranked", "c1"),
+		newPartitionTicket("game-a", "pool", "a2"),
+		newPartitionTicket("game-b", "pool", "b2"),
+		newPartitionTicket("game-a", "ranked", "c2"),
+	}
+	namespaceBefore := testutil.ToFloat64(rejectedPairings.WithLabelValues("namespace"))
+	poolBefore := testutil.ToFloat64(rejectedPairings.WithLabelValues("match_pool"))
+
+	// act
+	matches := makeTestMatches(t, GameMatchMaker{}, rules, tickets)
+
+	// assert
+	assert.Len(t, matches, 3)
+	assertSinglePartition(t, matches)
+	// a1 turns down b1, c1, b2 and c2, b1 turns down c1 and c2, c1 turns down nothing
+	assert.Equal(t, namespaceBefore+4, testutil.ToFloat64(rejectedPairings.WithLabelValues("namespace")))
+	assert.Equal(t, poolBefore+2, testutil.ToFloat64(rejectedPairings.WithLabelValues("match_pool")))
+}
+
+func TestMatchMakerPartitions(t *testing.T) {
+	// prepare
+	tickets := []matchmaker.Ticket{
+		newPartitionTicket("game-a", "pool", "a1"),
+		newPartitionTicket("game-b", "pool", "b1"),
+		newPartitionTicket("game-a", "ranked", "c1"),
+		newPartitionTicket("game-b", "pool", "b2"),
+		newPartitionTicket("game-a", "ranked", "c2"),
+		newPartitionTicket("game-a", "pool", "a2"),
+	}
+	namespaceBefore := testutil.ToFloat64(rejectedPairings.WithLabelValues("namespace"))
+	poolBefore := testutil.ToFloat64(rejectedPairings.WithLabelValues("match_pool"))
+
+	// act
+	matches := makeTestMatches(t, MatchMaker{}, GameRules{}, tickets)
+
+	// assert
+	assert.Len(t, matches, 3)
+	assertSinglePartition(t, matches)
+	// b1 turns down a1, c1 turns down a1 and b1, b2 turns down a1, c2 turns down a1
+	assert.Equal(t, namespaceBefore+3, testutil.ToFloat64(rejectedPairings.WithLabelValues("namespace")))
+	assert.Equal(t, poolBefore+2, testutil.ToFloat64(rejectedPairings.WithLabelValues("match_pool")))
+}
+
+func TestGameMatchMakerRejectedPairingsPerRoot(t *testing.T) {
+	// prepare
+	rules := GameRules{AllianceRule: AllianceRule{PlayerMinNumber: 2, PlayerMaxNumber: 3}}
+	tickets := []matchmaker.Ticket{
+		newPartitionTicket("game-a", "pool", "a1"),
+		newPartitionTicket("game-a", "pool", "a2"),
+		newPartitionTicket("game-a", "pool", "a3"),
+		newPartitionTicket("game-b", "pool", "b1"),
+		newPartitionTicket("game-b", "pool", "b2"),
+		newPartitionTicket("game-b", "pool", "b3"),
+		newPartitionTicket("game-b", "pool", "b4"),
+	}
+	namespaceBefore := testutil.ToFloat64(rejectedPairings.WithLabelValues("namespace"))
+
+	// act
+	matches := makeTestMatches(t, GameMatchMaker{}, rules, tickets)
+
+	// assert
+	assert.Len(t, matches, 2)
+	assertSinglePartition(t, matches)
+	// a1 turns down the four b tickets, b1 and then b4 find every a ticket matched already
+	assert.Equal(t, namespaceBefore+4, testutil.ToFloat64(rejectedPairings.WithLabelValues("namespace")))
+}
+
+func TestGameMatchMakerRejectedPairingsOncePerPair(t *testing.T) {
+	tests := []struct {
+		name     string
+		priority PriorityRule
+		stream   bool
+	}{
+		{name: "batch"},
+		{name: "incremental", stream: true},
+		{name: "incremental oldest first", priority: PriorityRule{OldestFirst: true}, stream: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// prepare
+			rules := GameRules{
+				AllianceRule: AllianceRule{MinNumber: 2, MaxNumber: 2, PlayerMinNumber: 6, PlayerMaxNumber: 6},
+				PriorityRule: tt.priority,
+				Incremental:  tt.stream,
+			}
+			var tickets []matchmaker.Ticket
+			for _, id := range []string{"1", "2", "3", "4", "5"} {
+				tickets = append(tickets, newPartitionTicket("game-a", "pool", "a"+id), newPartitionTicket("game-b", "pool", "b"+id))
+			}
+			namespaceBefore := testutil.ToFloat64(rejectedPairings.WithLabelValues("namespace"))
+
+			// act
+			matches := makeTestMatches(t, GameMatchMaker{}, rules, tickets)
+
+			// assert
+			assert.Empty(t, matches)
+			// every a ticket turns down every b ticket once, however many roots are tried
+			assert.Equal(t, namespaceBefore+25, testutil.ToFloat64(rejectedPairings.WithLabelValues("namespace")))
+		})
+	}
+}
+
+func TestProposeBackfillPartition(t *testing.T) {
+	// prepare
+	matched := newPartitionTicket("game-a", "pool", "a1")
+	backfillTicket := matchmaker.BackfillTicket{
+		TicketID:  GenerateUUID(),
+		MatchPool: "pool",
+		PartialMatch: matchmaker.Match{
+			Tickets: []matchmaker.Ticket{matched},
+			Teams:   []matchmaker.Team{{UserIDs: []player.ID{"a1"}}},
+		},
+	}
+	otherNamespace := newPartitionTicket("game-b", "pool", "b1")
+	otherPool := newPartitionTicket("game-a", "ranked", "c1")
+	pool := []matchmaker.Ticket{otherNamespace, otherPool, newPartitionTicket("game-a", "pool", "a2")}
+	rules := GameRules{AllianceRule: AllianceRule{PlayerMaxNumber: 3}}
+	namespaceBefore := testutil.ToFloat64(rejectedPairings.WithLabelValues("namespace"))
+	poolBefore := testutil.ToFloat64(rejectedPairings.WithLabelValues("match_pool"))
+
+	// act
+	proposal, remaining := proposeBackfill(NewScope(context.Background(), ""), backfillTicket, pool, rules)
+
+	// assert
+	if assert.NotNil(t, proposal) {
+		assert.Equal(t, []matchmaker.Team{{UserIDs: []player.ID{"a1", "a2"}}}, proposal.ProposedTeams)
+	}
+	assert.Equal(t, []string{otherNamespace.TicketID, otherPool.TicketID}, ticketIDs(remaining))
+	assert.Equal(t, namespaceBefore+1, testutil.ToFloat64(rejectedPairings.WithLabelValues("namespace")))
+	assert.Equal(t, poolBefore+1, testutil.ToFloat64(rejectedPairings.WithLabelValues("match_pool")))
+}
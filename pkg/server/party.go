@@ -0,0 +1,217 @@
+// Copyright (c) 2023 AccelByte Inc. All Rights Reserved.
+// This is licensed software from AccelByte Inc, for limitations
+// and restrictions contact your company contract manager.
+
+package server
+
+import (
+	"matchmaking-function-grpc-plugin-server-go/pkg/matchmaker"
+	"matchmaking-function-grpc-plugin-server-go/pkg/player"
+)
+
+// partyKeys returns the parties a ticket belongs to, its party session and the parties of its players. Keys are
+// scoped to the ticket's partition, as tickets of different partitions never share a match anyway
+func partyKeys(ticket matchmaker.Ticket) []string {
+	scope := ticket.Namespace + "/" + ticket.MatchPool + "/"
+	var keys []string
+	if ticket.PartySessionID != "" {
+		keys = append(keys, scope+"session:"+ticket.PartySessionID)
+	}
+	for _, p := range ticket.Players {
+		if p.PartyID != "" {
+			keys = append(keys, scope+"party:"+p.PartyID)
+		}
+	}
+	return keys
+}
+
+// parties holds the tickets joined into one, by the ID of the joined ticket
+type parties map[string][]matchmaker.Ticket
+
+// joinParties joins the tickets sharing a party, directly or through other tickets, into a single ticket seating all
+// their players so no match can split them. The joined ticket takes the ID and attributes of the first ticket of the
+// party and the creation time of the oldest. The returned parties expand the joined tickets again
+func joinParties(tickets []matchmaker.Ticket) ([]matchmaker.Ticket, parties) {
+	leaders := make([]int, len(tickets))
+	for i := range leaders {
+		leaders[i] = i
+	}
+	var leader func(i int) int
+	leader = func(i int) int {
+		if leaders[i] != i {
+			leaders[i] = leader(leaders[i])
+		}
+		return leaders[i]
+	}
+	owners := map[string]int{}
+	for i, ticket := range tickets {
+		for _, key := range partyKeys(ticket) {
+			owner, ok := owners[key]
+			if !ok {
+				owners[key] = i
+				continue
+			}
+			if a, b := leader(owner), leader(i); a != b {
+				if a < b {
+					leaders[b] = a
+				} else {
+					leaders[a] = b
+				}
+			}
+		}
+	}
+
+	members := map[int][]matchmaker.Ticket{}
+	for i, ticket := range tickets {
+		members[leader(i)] = append(members[leader(i)], ticket)
+	}
+	joined := make([]matchmaker.Ticket, 0, len(members))
+	p := parties{}
+	for i, ticket := range tickets {
+		if leader(i) != i {
+			continue
+		}
+		if len(members[i]) == 1 {
+			joined = append(joined, ticket)
+			continue
+		}
+		p[ticket.TicketID] = members[i]
+		joined = append(joined, joinTickets(members[i]))
+	}
+	return joined, p
+}
+
+// joinTickets returns the ticket seating the players of all the tickets. Its latencies are the worst of the tickets in
+// the regions they all report, left empty when they share no region so that the region rule refuses the party
+func joinTickets(tickets []matchmaker.Ticket) matchmaker.Ticket {
+	joined := tickets[0]
+	joined.Players = nil
+	var latencies map[string]int64
+	for _, ticket := range tickets {
+		joined.Players = append(joined.Players, ticket.Players...)
+		if ticket.CreatedAt.Before(joined.CreatedAt) {
+			joined.CreatedAt = ticket.CreatedAt
+		}
+		if ticket.Latencies == nil {
+			continue
+		}
+		if latencies == nil {
+			latencies = map[string]int64{}
+			for region, latency := range ticket.Latencies {
+				latencies[region] = latency
+			}
+			continue
+		}
+		for region, worst := range latencies {
+			latency, ok := ticket.Latencies[region]
+			if !ok {
+				delete(latencies, region)
+			} else if latency > worst {
+				latencies[region] = latency
+			}
+		}
+	}
+	if latencies != nil {
+		joined.Latencies = latencies
+	}
+	return joined
+}
+
+// expand replaces the joined tickets with the tickets of their party
+func (p parties) expand(tickets []matchmaker.Ticket) []matchmaker.Ticket {
+	expanded := make([]matchmaker.Ticket, 0, len(tickets))
+	for _, ticket := range tickets {
+		if members, ok := p[ticket.TicketID]; ok {
+			expanded = append(expanded, members...)
+			continue
+		}
+		expanded = append(expanded, ticket)
+	}
+	return expanded
+}
+
+// keys returns the parties of a ticket, including those of the tickets joined into it
+func (p parties) keys(ticket matchmaker.Ticket) []string {
+	var keys []string
+	for _, member := range p.expand([]matchmaker.Ticket{ticket}) {
+		keys = append(keys, partyKeys(member)...)
+	}
+	return keys
+}
+
+// partySize returns the size of the party a ticket counts as in a team
+func partySize(ticket matchmaker.Ticket) int {
+	return len(ticket.Players)
+}
+
+// limits returns the most parties of each size a team may take, sizes without a limit are left out
+func (r PartyRule) limits() map[int]int {
+	limits := map[int]int{}
+	for _, limit := range r.Limits {
+		limits[limit.Size] = limit.MaxPerTeam
+	}
+	return limits
+}
+
+// allows reports whether every team keeps within the limits of the rule
+func (r PartyRule) allows(teams [][]matchmaker.Ticket) bool {
+	for _, team := range teams {
+		window := newPartyLimitWindow(r)
+		for _, ticket := range team {
+			if !window.fits(ticket) {
+				return false
+			}
+			window.add(ticket)
+		}
+	}
+	return true
+}
+
+// partyLimitWindow is the ticketFilter keeping one team within the party limits of the rule
+type partyLimitWindow struct {
+	limits map[int]int
+	counts map[int]int
+}
+
+func newPartyLimitWindow(rule PartyRule) *partyLimitWindow {
+	return &partyLimitWindow{limits: rule.limits(), counts: map[int]int{}}
+}
+
+func (w *partyLimitWindow) fits(ticket matchmaker.Ticket) bool {
+	limit, ok := w.limits[partySize(ticket)]
+	return !ok || w.counts[partySize(ticket)] < limit
+}
+
+func (w *partyLimitWindow) add(ticket matchmaker.Ticket) {
+	w.counts[partySize(ticket)]++
+}
+
+// splitsParty reports whether a party would have players on more than one of the teams
+func splitsParty(teams [][]matchmaker.Ticket) bool {
+	teamOf := map[string]int{}
+	for team, tickets := range teams {
+		for _, ticket := range tickets {
+			for _, key := range partyKeys(ticket) {
+				if other, ok := teamOf[key]; ok && other != team {
+					return true
+				}
+				teamOf[key] = team
+			}
+		}
+	}
+	return false
+}
+
+// teamOfPlayers returns the index of the team seating any of the players, or -1
+func teamOfPlayers(teams []matchmaker.Team, players []player.PlayerData) int {
+	for i, team := range teams {
+		for _, id := range team.UserIDs {
+			for _, p := range players {
+				if p.PlayerID == id {
+					return i
+				}
+			}
+		}
+	}
+	return -1
+}
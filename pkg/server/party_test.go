@@ -0,0 +1,288 @@
+// Copyright (c) 2023 AccelByte Inc. All Rights Reserved.
+// This is licensed software from AccelByte Inc, for limitations
+// and restrictions contact your company contract manager.
+
+package server
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"matchmaking-function-grpc-plugin-server-go/pkg/matchmaker"
+	"matchmaking-function-grpc-plugin-server-go/pkg/player"
+)
+
+func newPartyTicket(partySessionID string, playerIDs ...string) matchmaker.Ticket {
+	ticket := newTestTicket(playerIDs...)
+	ticket.PartySessionID = partySessionID
+	return ticket
+}
+
+func ticketIDs(tickets []matchmaker.Ticket) []string {
+	ids := make([]string, 0, len(tickets))
+	for _, ticket := range tickets {
+		ids = append(ids, ticket.TicketID)
+	}
+	return ids
+}
+
+func TestJoinParties(t *testing.T) {
+	// prepare
+	now := time.Now()
+	a := newPartyTicket("s1", "a1")
+	b := newPartyTicket("s1", "b1")
+	b.CreatedAt = now.Add(-time.Minute)
+	c := newTestTicket("c1")
+	c.Players[0].PartyID = "p1"
+	d := newPartyTicket("s2", "d1")
+	d.Players[0].PartyID = "p1"
+	e := newPartyTicket("s2", "e1")
+	f := newTestTicket("f1")
+	otherPool := newPartyTicket("s1", "g1")
+	otherPool.MatchPool = "other"
+	tickets := []matchmaker.Ticket{a, c, b, e, f, d, otherPool}
+
+	// act
+	joined, p := joinParties(tickets)
+
+	// assert
+	if assert.Len(t, joined, 4) {
+		assert.Equal(t, a.TicketID, joined[0].TicketID)
+		assert.Equal(t, []player.ID{"a1", "b1"}, mapPlayerIDs(joined[:1]))
+		assert.Equal(t, b.CreatedAt, joined[0].CreatedAt)
+		assert.Equal(t, []player.ID{"c1", "e1", "d1"}, mapPlayerIDs(joined[1:2]))
+		assert.Equal(t, f.TicketID, joined[2].TicketID)
+		assert.Equal(t, otherPool.TicketID, joined[3].TicketID)
+	}
+	assert.ElementsMatch(t, ticketIDs(tickets), ticketIDs(p.expand(joined)))
+}
+
+func TestJoinTicketsLatencies(t *testing.T) {
+	// prepare
+	a := newTestTicket("a1")
+	a.Latencies = map[string]int64{"us-east-1": 30, "eu-west-1": 100}
+	b := newTestTicket("b1")
+	b.Latencies = map[string]int64{"us-east-1": 50}
+	c := newTestTicket("c1")
+
+	// act
+	joined := joinTickets([]matchmaker.Ticket{a, b, c})
+
+	// assert
+	assert.Equal(t, map[string]int64{"us-east-1": 50}, joined.Latencies)
+	assert.Equal(t, map[string]int64{"us-east-1": 30, "eu-west-1": 100}, a.Latencies)
+}
+
+func TestJoinTicketsWithoutSharedRegion(t *testing.T) {
+	// prepare
+	a := newTestTicket("a1")
+	a.Latencies = map[string]int64{"us-east-1": 30}
+	b := newTestTicket("b1")
+	b.Latencies = map[string]int64{"eu-west-1": 40}
+	other := newTestTicket("c1")
+	other.Latencies = map[string]int64{"us-east-1": 20, "eu-west-1": 20}
+	rule := RegionRule{MaxLatency: 100}
+
+	// act
+	joined := joinTickets([]matchmaker.Ticket{a, b})
+	_, allowed := matchRegions([]matchmaker.Ticket{joined, other}, rule, time.Now())
+	_, allowedWithoutRule := matchRegions([]matchmaker.Ticket{joined, other}, RegionRule{}, time.Now())
+
+	// assert
+	assert.NotNil(t, joined.Latencies)
+	assert.Empty(t, joined.Latencies)
+	assert.False(t, allowed)
+	assert.True(t, allowedWithoutRule)
+}
+
+func teamOf(match matchmaker.Match, id player.ID) int {
+	for i, team := range match.Teams {
+		for _, userID := range team.UserIDs {
+			if userID == id {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func TestGameMatchMakerKeepsPartiesTogether(t *testing.T) {
+	// prepare
+	rules := GameRules{AllianceRule: AllianceRule{MinNumber: 2, MaxNumber: 2, PlayerMinNumber: 2, PlayerMaxNumber: 2}}
+	tickets := []matchmaker.Ticket{
+		newPartyTicket("s1", "a1"),
+		newPartyTicket("s1", "a2"),
+		newTestTicket("b1"),
+		newTestTicket("b2"),
+	}
+
+	// act
+	matches := makeTestMatches(t, GameMatchMaker{}, rules, tickets)
+
+	// assert
+	if assert.Len(t, matches, 1) && assert.Len(t, matches[0].Teams, 2) {
+		assert.ElementsMatch(t, ticketIDs(tickets), ticketIDs(matches[0].Tickets))
+		assert.Equal(t, teamOf(matches[0], "a1"), teamOf(matches[0], "a2"))
+	}
+}
+
+func TestGameMatchMakerIncrementalKeepsPartiesTogether(t *testing.T) {
+	// prepare
+	rules := GameRules{
+		AllianceRule: AllianceRule{MinNumber: 2, MaxNumber: 2, PlayerMinNumber: 2, PlayerMaxNumber: 2},
+		Incremental:  true,
+	}
+	tickets := []matchmaker.Ticket{
+		newPartyTicket("s1", "a1"),
+		newTestTicket("b1"),
+		newTestTicket("b2"),
+		newPartyTicket("s1", "a2"),
+	}
+
+	// act
+	matches := makeTestMatches(t, GameMatchMaker{}, rules, tickets)
+
+	// assert
+	if assert.Len(t, matches, 1) {
+		assert.Len(t, matches[0].Tickets, 4)
+		assert.Equal(t, teamOf(matches[0], "a1"), teamOf(matches[0], "a2"))
+		assert.Equal(t, teamOf(matches[0], "b1"), teamOf(matches[0], "b2"))
+	}
+}
+
+func TestGameMatchMakerIncrementalWaitsForWholeParty(t *testing.T) {
+	// prepare
+	rules := GameRules{
+		AllianceRule: AllianceRule{MinNumber: 2, MaxNumber: 2, PlayerMinNumber: 1, PlayerMaxNumber: 3},
+		Incremental:  true,
+	}
+	a1 := newPartyTicket("s1", "a1")
+	a2 := newPartyTicket("s1", "a2")
+	a2.Players[0].PartyID = "p1"
+	a3 := newTestTicket("a3")
+	a3.Players[0].PartyID = "p1"
+	tickets := []matchmaker.Ticket{
+		a1,
+		newTestTicket("b1"),
+		a3,
+		newTestTicket("b2"),
+		newTestTicket("b3"),
+		newTestTicket("c1"),
+		a2,
+	}
+
+	// act
+	matches := makeTestMatches(t, GameMatchMaker{}, rules, tickets)
+
+	// assert
+	var partyMatches []matchmaker.Match
+	for _, match := range matches {
+		if teamOf(match, "a1") >= 0 || teamOf(match, "a2") >= 0 || teamOf(match, "a3") >= 0 {
+			partyMatches = append(partyMatches, match)
+		}
+	}
+	if assert.Len(t, partyMatches, 1) {
+		match := partyMatches[0]
+		assert.Subset(t, ticketIDs(match.Tickets), ticketIDs([]matchmaker.Ticket{a1, a2, a3}))
+		assert.Equal(t, teamOf(match, "a1"), teamOf(match, "a2"))
+		assert.Equal(t, teamOf(match, "a1"), teamOf(match, "a3"))
+	}
+}
+
+func TestGameMatchMakerPartyLimits(t *testing.T) {
+	// prepare
+	rules := GameRules{
+		AllianceRule: AllianceRule{MinNumber: 2, MaxNumber: 2, PlayerMinNumber: 4, PlayerMaxNumber: 4},
+		PartyRule:    PartyRule{Limits: []PartyLimit{{Size: 2, MaxPerTeam: 1}}},
+	}
+	tickets := []matchmaker.Ticket{
+		newTestTicket("p1", "p2"),
+		newTestTicket("p3", "p4"),
+		newTestTicket("p5", "p6"),
+		newTestTicket("s1"),
+		newTestTicket("s2"),
+		newTestTicket("s3"),
+		newTestTicket("s4"),
+	}
+
+	// act
+	matches := makeTestMatches(t, GameMatchMaker{}, rules, tickets)
+
+	// assert
+	if assert.Len(t, matches, 1) && assert.Len(t, matches[0].Teams, 2) {
+		for _, team := range matches[0].Teams {
+			paired := 0
+			for _, id := range team.UserIDs {
+				if strings.HasPrefix(string(id), "p") {
+					paired++
+				}
+			}
+			assert.Equal(t, 2, paired, team.UserIDs)
+		}
+	}
+}
+
+func TestProposeBackfillKeepsParty(t *testing.T) {
+	// prepare
+	backfillTicket := matchmaker.BackfillTicket{
+		TicketID: GenerateUUID(),
+		PartialMatch: matchmaker.Match{
+			Tickets: []matchmaker.Ticket{newPartyTicket("s1", "a1"), newTestTicket("x1"), newTestTicket("b1")},
+			Teams: []matchmaker.Team{
+				{UserIDs: []player.ID{"a1", "x1"}},
+				{UserIDs: []player.ID{"b1"}},
+			},
+		},
+	}
+	mate := newPartyTicket("s1", "a2")
+	otherMate := newPartyTicket("s1", "a3")
+	rules := GameRules{AllianceRule: AllianceRule{MinNumber: 2, MaxNumber: 2, PlayerMaxNumber: 3}}
+
+	// act
+	proposal, remaining := proposeBackfill(NewScope(context.Background(), ""), backfillTicket, []matchmaker.Ticket{mate, newTestTicket("c1")}, rules)
+	tooBigProposal, tooBigRemaining := proposeBackfill(NewScope(context.Background(), ""), backfillTicket, []matchmaker.Ticket{mate, newTestTicket("c1"), otherMate}, rules)
+
+	// assert
+	if assert.NotNil(t, proposal) {
+		assert.Equal(t, []matchmaker.Team{
+			{UserIDs: []player.ID{"a1", "x1", "a2"}},
+			{UserIDs: []player.ID{"b1", "c1"}},
+		}, proposal.ProposedTeams)
+	}
+	assert.Empty(t, remaining)
+	if assert.NotNil(t, tooBigProposal) {
+		assert.Equal(t, []matchmaker.Team{
+			{UserIDs: []player.ID{"a1", "x1"}},
+			{UserIDs: []player.ID{"b1", "c1"}},
+		}, tooBigProposal.ProposedTeams)
+	}
+	assert.ElementsMatch(t, []string{mate.TicketID, otherMate.TicketID}, ticketIDs(tooBigRemaining))
+}
+
+func TestMatchMakerKeepsPartiesTogether(t *testing.T) {
+	// prepare
+	tickets := []matchmaker.Ticket{
+		newPartyTicket("s1", "a1"),
+		newTestTicket("b1"),
+		newTestTicket("c1"),
+		newPartyTicket("s1", "a2"),
+		newPartyTicket("s2", "d1"),
+		newPartyTicket("s2", "d2"),
+		newPartyTicket("s2", "d3"),
+		newTestTicket("e1"),
+		newPartyTicket("s3", "f1", "f2"),
+	}
+
+	// act
+	matches := makeTestMatches(t, New(), GameRules{}, tickets)
+
+	// assert
+	var got [][]player.ID
+	for _, match := range matches {
+		got = append(got, mapPlayerIDs(match.Tickets))
+	}
+	assert.ElementsMatch(t, [][]player.ID{{"b1", "c1"}, {"a1", "a2"}, {"e1", "f1", "f2"}}, got)
+}
@@ -0,0 +1,77 @@
+// Copyright (c) 2023 AccelByte Inc. All Rights Reserved.
+// This is licensed software from AccelByte Inc, for limitations
+// and restrictions contact your company contract manager.
+
+package server
+
+import (
+	"sort"
+	"time"
+
+	"golang.org/x/exp/maps"
+	"matchmaking-function-grpc-plugin-server-go/pkg/matchmaker"
+)
+
+// relaxedFor returns the rules for a match seeded with the given ticket, relaxed by every step of the priority rule
+// the ticket has waited for. Steps only ever loosen a rule, a step asking for less than the rule already allows is
+// ignored
+func (r GameRules) relaxedFor(seed matchmaker.Ticket, now time.Time) GameRules {
+	waited := now.Sub(seed.CreatedAt)
+	for _, step := range r.PriorityRule.Relaxations {
+		if waited < time.Duration(step.AfterSeconds)*time.Second {
+			break
+		}
+		if step.MinNumber > 0 && step.MinNumber < r.AllianceRule.MinNumber {
+			r.AllianceRule.MinNumber = step.MinNumber
+		}
+		if step.PlayerMinNumber > 0 && step.PlayerMinNumber < r.AllianceRule.PlayerMinNumber {
+			r.AllianceRule.PlayerMinNumber = step.PlayerMinNumber
+		}
+		if step.MaxSpread > r.SkillRule.MaxSpread {
+			r.SkillRule.MaxSpread = step.MaxSpread
+			if r.SkillRule.MaxWidenedSpread > 0 && r.SkillRule.MaxWidenedSpread < step.MaxSpread {
+				r.SkillRule.MaxWidenedSpread = step.MaxSpread
+			}
+		}
+		if step.MaxLatency > r.RegionRule.MaxLatency {
+			r.RegionRule.MaxLatency = step.MaxLatency
+			if r.RegionRule.MaxExpandedLatency > 0 && r.RegionRule.MaxExpandedLatency < step.MaxLatency {
+				r.RegionRule.MaxExpandedLatency = step.MaxLatency
+			}
+		}
+	}
+	return r
+}
+
+// oldestTicket takes the oldest ticket fitting the filter out of the buckets of at most maxPlayerCount players
+func oldestTicket(buckets map[int]*queue, maxPlayerCount int, filter ticketFilter) *matchmaker.Ticket {
+	bucketKeys := maps.Keys(buckets)
+	sort.Ints(bucketKeys)
+
+	var oldest *queue
+	var oldestCreatedAt time.Time
+	for i := len(bucketKeys) - 1; i >= 0; i-- {
+		if bucketKeys[i] > maxPlayerCount {
+			continue
+		}
+		ticket := buckets[bucketKeys[i]].peek(filter)
+		if ticket != nil && (oldest == nil || ticket.CreatedAt.Before(oldestCreatedAt)) {
+			oldest, oldestCreatedAt = buckets[bucketKeys[i]], ticket.CreatedAt
+		}
+	}
+	if oldest == nil {
+		return nil
+	}
+	return oldest.pop(filter)
+}
+
+// insertByAge inserts the ticket into tickets ordered oldest first, after the tickets created at the same time
+func insertByAge(tickets []matchmaker.Ticket, ticket matchmaker.Ticket) []matchmaker.Ticket {
+	i := sort.Search(len(tickets), func(i int) bool {
+		return tickets[i].CreatedAt.After(ticket.CreatedAt)
+	})
+	tickets = append(tickets, matchmaker.Ticket{})
+	copy(tickets[i+1:], tickets[i:])
+	tickets[i] = ticket
+	return tickets
+}
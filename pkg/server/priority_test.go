@@ -0,0 +1,191 @@
+// Copyright (c) 2023 AccelByte Inc. All Rights Reserved.
+// This is licensed software from AccelByte Inc, for limitations
+// and restrictions contact your company contract manager.
+
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"matchmaking-function-grpc-plugin-server-go/pkg/matchmaker"
+	"matchmaking-function-grpc-plugin-server-go/pkg/player"
+)
+
+func TestRelaxedFor(t *testing.T) {
+	now := time.Now()
+	rules := GameRules{
+		AllianceRule: AllianceRule{MinNumber: 2, MaxNumber: 2, PlayerMinNumber: 4, PlayerMaxNumber: 4},
+		SkillRule:    SkillRule{Attribute: "mmr", MaxSpread: 10, MaxWidenedSpread: 20},
+		RegionRule:   RegionRule{MaxLatency: 50},
+		PriorityRule: PriorityRule{Relaxations: []Relaxation{
+			{AfterSeconds: 30, PlayerMinNumber: 3, MaxSpread: 15},
+			{AfterSeconds: 60, MinNumber: 1, PlayerMinNumber: 5, MaxSpread: 40, MaxLatency: 100},
+		}},
+	}
+	tests := []struct {
+		name   string
+		waited time.Duration
+		want   func(r *GameRules)
+	}{
+		{name: "fresh", waited: 0, want: func(r *GameRules) {}},
+		{name: "first step", waited: 45 * time.Second, want: func(r *GameRules) {
+			r.AllianceRule.PlayerMinNumber = 3
+			r.SkillRule.MaxSpread = 15
+		}},
+		{name: "both steps", waited: 2 * time.Minute, want: func(r *GameRules) {
+			r.AllianceRule.MinNumber = 1
+			r.AllianceRule.PlayerMinNumber = 3
+			r.SkillRule.MaxSpread = 40
+			r.SkillRule.MaxWidenedSpread = 40
+			r.RegionRule.MaxLatency = 100
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// prepare
+			want := rules
+			tt.want(&want)
+			seed := matchmaker.Ticket{CreatedAt: now.Add(-tt.waited)}
+
+			// act
+			got := rules.relaxedFor(seed, now)
+
+			// assert
+			assert.Equal(t, want, got)
+		})
+	}
+}
+
+func TestGameMatchMakerOldestFirst(t *testing.T) {
+	// prepare
+	now := time.Now()
+	old := newTestTicket("o1")
+	old.CreatedAt = now.Add(-time.Minute)
+	tickets := []matchmaker.Ticket{newTestTicket("p1", "p2"), old, newTestTicket("n1")}
+	rules := GameRules{AllianceRule: AllianceRule{PlayerMinNumber: 2, PlayerMaxNumber: 2}}
+	oldestFirst := rules
+	oldestFirst.PriorityRule.OldestFirst = true
+
+	// act
+	biggest := makeTestMatches(t, GameMatchMaker{}, rules, tickets)
+	oldest := makeTestMatches(t, GameMatchMaker{}, oldestFirst, tickets)
+
+	// assert
+	if assert.Len(t, biggest, 2) {
+		assert.ElementsMatch(t, []player.ID{"p1", "p2"}, biggest[0].Teams[0].UserIDs)
+	}
+	if assert.Len(t, oldest, 2) {
+		assert.ElementsMatch(t, []player.ID{"o1", "n1"}, oldest[0].Teams[0].UserIDs)
+	}
+}
+
+func TestGameMatchMakerOldestRootNearestSkill(t *testing.T) {
+	// prepare
+	now := time.Now()
+	tickets := []matchmaker.Ticket{
+		newSkillTicket("a1", 0, now.Add(-4*time.Second)),
+		newSkillTicket("b1", 90, now.Add(-3*time.Second)),
+		newSkillTicket("c1", 5, now.Add(-2*time.Second)),
+		newSkillTicket("d1", 95, now.Add(-time.Second)),
+	}
+	rules := GameRules{
+		AllianceRule: AllianceRule{PlayerMinNumber: 2, PlayerMaxNumber: 2},
+		SkillRule:    SkillRule{Attribute: "mmr", MaxSpread: 100},
+		PriorityRule: PriorityRule{OldestFirst: true},
+	}
+
+	// act
+	matches := makeTestMatches(t, GameMatchMaker{}, rules, tickets)
+
+	// assert, the oldest tickets seed the matches and take the closest skill the spread allows, not the next oldest
+	if assert.Len(t, matches, 2) {
+		assert.ElementsMatch(t, []player.ID{"a1", "c1"}, matches[0].Teams[0].UserIDs)
+		assert.ElementsMatch(t, []player.ID{"b1", "d1"}, matches[1].Teams[0].UserIDs)
+	}
+}
+
+func TestGameMatchMakerRelaxesForStarvingTickets(t *testing.T) {
+	// prepare
+	now := time.Now()
+	old := newTestTicket("o1")
+	old.CreatedAt = now.Add(-time.Minute)
+	rules := GameRules{
+		AllianceRule: AllianceRule{PlayerMinNumber: 4, PlayerMaxNumber: 4},
+		PriorityRule: PriorityRule{OldestFirst: true, Relaxations: []Relaxation{{AfterSeconds: 30, PlayerMinNumber: 3}}},
+	}
+	fresh := []matchmaker.Ticket{newTestTicket("a1"), newTestTicket("b1"), newTestTicket("c1")}
+	before := waitObservations(t, MatchLogicGame)
+
+	// act
+	withoutOld := makeTestMatches(t, GameMatchMaker{}, rules, fresh)
+	withOld := makeTestMatches(t, GameMatchMaker{}, rules, append([]matchmaker.Ticket{old}, fresh[:2]...))
+
+	// assert
+	assert.Empty(t, withoutOld)
+	if assert.Len(t, withOld, 1) {
+		assert.ElementsMatch(t, []player.ID{"o1", "a1", "b1"}, withOld[0].Teams[0].UserIDs)
+	}
+	assert.Equal(t, uint64(3), waitObservations(t, MatchLogicGame)-before)
+}
+
+func waitObservations(t *testing.T, matchLogic string) uint64 {
+	t.Helper()
+	var metric dto.Metric
+	if err := ticketWaitSeconds.WithLabelValues(matchLogic).(prometheus.Histogram).Write(&metric); err != nil {
+		t.Fatal(err)
+	}
+	return metric.GetHistogram().GetSampleCount()
+}
+
+func TestGameMatchMakerIncrementalRelaxes(t *testing.T) {
+	tests := []struct {
+		name        string
+		oldestFirst bool
+		order       []string
+	}{
+		{name: "old ticket arrives last", order: []string{"n1", "o1"}},
+		{name: "oldest seeds", oldestFirst: true, order: []string{"o1", "n1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// prepare
+			now := time.Now()
+			tickets := map[string]matchmaker.Ticket{
+				"o1": newSkillTicket("o1", 0, now.Add(-time.Minute)),
+				"n1": newSkillTicket("n1", 50, now),
+			}
+			rules := GameRules{
+				AllianceRule: AllianceRule{MinNumber: 2, MaxNumber: 2, PlayerMinNumber: 1, PlayerMaxNumber: 1},
+				SkillRule:    SkillRule{Attribute: "mmr", MaxSpread: 10},
+				PriorityRule: PriorityRule{OldestFirst: tt.oldestFirst, Relaxations: []Relaxation{{AfterSeconds: 30, MaxSpread: 100}}},
+				Incremental:  true,
+			}
+			ticketProvider := matchTicketProvider{channelTickets: make(chan matchmaker.Ticket)}
+
+			// act
+			matches := GameMatchMaker{}.MakeMatches(NewScope(context.Background(), ""), ticketProvider, rules)
+			for _, id := range tt.order {
+				ticketProvider.channelTickets <- tickets[id]
+			}
+			var early matchmaker.Match
+			select {
+			case early = <-matches:
+			case <-time.After(time.Second):
+				t.Fatal("no match before the end of the stream")
+			}
+			close(ticketProvider.channelTickets)
+			for range matches {
+			}
+
+			// assert
+			if assert.Len(t, early.Teams, 2) {
+				assert.ElementsMatch(t, []player.ID{"o1", "n1"}, append(early.Teams[0].UserIDs, early.Teams[1].UserIDs...))
+			}
+		})
+	}
+}
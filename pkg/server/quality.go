@@ -0,0 +1,134 @@
+// Copyright (c) 2023 AccelByte Inc. All Rights Reserved.
+// This is licensed software from AccelByte Inc, for limitations
+// and restrictions contact your company contract manager.
+
+package server
+
+import (
+	"math"
+	"time"
+
+	"matchmaking-function-grpc-plugin-server-go/pkg/matchmaker"
+	"matchmaking-function-grpc-plugin-server-go/pkg/player"
+)
+
+// qualityAttribute is the match attribute holding the quality record of a match
+const qualityAttribute = "quality"
+
+// Fields of the quality record
+const (
+	qualitySkillSpread      = "skill_spread"
+	qualityTeamBalanceDelta = "team_balance_delta"
+	qualityMaxLatency       = "max_latency"
+	qualityAvgLatency       = "avg_latency"
+	qualityLongestWait      = "longest_wait_seconds"
+	qualityRulesRelaxed     = "rules_relaxed"
+)
+
+// measureQuality returns the quality record of a match formed under the rules: the spread of the ticket skills, the
+// difference between the best and worst team's average skill, the highest and average latency to the preferred
+// region, how long the oldest ticket waited, and which rules were relaxed to form the match
+func measureQuality(match matchmaker.Match, rules GameRules, now time.Time) map[string]interface{} {
+	spread, delta := skillQuality(match, rules.SkillRule)
+	maxLatency, avgLatency := latencyQuality(match)
+	longestWait := 0.0
+	for _, ticket := range match.Tickets {
+		longestWait = math.Max(longestWait, now.Sub(ticket.CreatedAt).Seconds())
+	}
+	return map[string]interface{}{
+		qualitySkillSpread:      spread,
+		qualityTeamBalanceDelta: delta,
+		qualityMaxLatency:       maxLatency,
+		qualityAvgLatency:       avgLatency,
+		qualityLongestWait:      longestWait,
+		qualityRulesRelaxed:     relaxedRules(match, rules, spread, maxLatency),
+	}
+}
+
+// skillQuality returns the skill spread of the tickets and the skill difference between the teams, both 0 without a
+// skill rule
+func skillQuality(match matchmaker.Match, rule SkillRule) (float64, float64) {
+	if !rule.enabled() || len(match.Tickets) == 0 {
+		return 0, 0
+	}
+	playerSkills := map[player.ID]float64{}
+	low, high := math.Inf(1), math.Inf(-1)
+	for _, ticket := range match.Tickets {
+		skill := ticketSkill(ticket, rule.Attribute)
+		low, high = math.Min(low, skill), math.Max(high, skill)
+		for _, p := range ticket.Players {
+			playerSkills[p.PlayerID] = skill
+		}
+	}
+
+	lowTeam, highTeam := math.Inf(1), math.Inf(-1)
+	for _, team := range match.Teams {
+		if len(team.UserIDs) == 0 {
+			continue
+		}
+		total := 0.0
+		for _, id := range team.UserIDs {
+			total += playerSkills[id]
+		}
+		average := total / float64(len(team.UserIDs))
+		lowTeam, highTeam = math.Min(lowTeam, average), math.Max(highTeam, average)
+	}
+	if len(match.Teams) < 2 {
+		return high - low, 0
+	}
+	return high - low, highTeam - lowTeam
+}
+
+// latencyQuality returns the highest and the average latency of the tickets to the preferred region of the match,
+// counting the tickets that report one
+func latencyQuality(match matchmaker.Match) (float64, float64) {
+	if len(match.RegionPreference) == 0 {
+		return 0, 0
+	}
+	region := match.RegionPreference[0]
+	max, total, count := 0.0, 0.0, 0
+	for _, ticket := range match.Tickets {
+		latency, ok := ticket.Latencies[region]
+		if !ok {
+			continue
+		}
+		max = math.Max(max, float64(latency))
+		total += float64(latency)
+		count++
+	}
+	if count == 0 {
+		return 0, 0
+	}
+	return max, total / float64(count)
+}
+
+// relaxedRules names the rules the match needed relaxed: the alliance minimums when a team or the match has fewer
+// players than they ask, and the skill spread or region latency when the match goes beyond them
+func relaxedRules(match matchmaker.Match, rules GameRules, spread float64, maxLatency float64) []interface{} {
+	relaxed := []interface{}{}
+	alliance := rules.AllianceRule
+	short := len(match.Teams) < alliance.MinNumber
+	for _, team := range match.Teams {
+		short = short || len(team.UserIDs) < alliance.PlayerMinNumber
+	}
+	if short {
+		relaxed = append(relaxed, "alliance")
+	}
+	if rules.SkillRule.enabled() && spread > rules.SkillRule.MaxSpread {
+		relaxed = append(relaxed, "skill")
+	}
+	if rules.RegionRule.enabled() && maxLatency > float64(rules.RegionRule.MaxLatency) {
+		relaxed = append(relaxed, "region")
+	}
+	return relaxed
+}
+
+// setQuality writes the quality record of the match formed under the rules to its match attributes
+func setQuality(match *matchmaker.Match, rules GameRules, now time.Time) {
+	attributes := map[string]interface{}{}
+	for key, value := range match.MatchAttributes {
+		attributes[key] = value
+	}
+	attributes[qualityAttribute] = measureQuality(*match, rules, now)
+	match.MatchAttributes = attributes
+}
@@ -0,0 +1,100 @@
+// Copyright (c) 2023 AccelByte Inc. All Rights Reserved.
+// This is licensed software from AccelByte Inc, for limitations
+// and restrictions contact your company contract manager.
+
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/structpb"
+	"matchmaking-function-grpc-plugin-server-go/pkg/matchmaker"
+	"matchmaking-function-grpc-plugin-server-go/pkg/player"
+)
+
+func qualityObservations(t *testing.T, field string, matchLogic string) uint64 {
+	t.Helper()
+	var metric dto.Metric
+	if err := matchQuality[field].WithLabelValues(matchLogic).(prometheus.Histogram).Write(&metric); err != nil {
+		t.Fatal(err)
+	}
+	return metric.GetHistogram().GetSampleCount()
+}
+
+func TestMeasureQuality(t *testing.T) {
+	// prepare
+	now := time.Now()
+	a := newSkillTicket("a1", 10, now.Add(-30*time.Second))
+	a.Latencies = map[string]int64{"us-east-1": 40}
+	b := newSkillTicket("b1", 14, now.Add(-5*time.Second))
+	b.Latencies = map[string]int64{"us-east-1": 80}
+	c := newSkillTicket("c1", 16, now)
+	match := matchmaker.Match{
+		Tickets:          []matchmaker.Ticket{a, b, c},
+		Teams:            []matchmaker.Team{{UserIDs: []player.ID{"a1", "b1"}}, {UserIDs: []player.ID{"c1"}}},
+		RegionPreference: []string{"us-east-1"},
+	}
+	rules := GameRules{
+		AllianceRule: AllianceRule{MinNumber: 2, MaxNumber: 2, PlayerMinNumber: 2, PlayerMaxNumber: 2},
+		SkillRule:    SkillRule{Attribute: "mmr", MaxSpread: 4},
+		RegionRule:   RegionRule{MaxLatency: 100},
+	}
+
+	// act
+	quality := measureQuality(match, rules, now)
+
+	// assert
+	assert.Equal(t, map[string]interface{}{
+		qualitySkillSpread:      6.0,
+		qualityTeamBalanceDelta: 4.0,
+		qualityMaxLatency:       80.0,
+		qualityAvgLatency:       60.0,
+		qualityLongestWait:      30.0,
+		qualityRulesRelaxed:     []interface{}{"alliance", "skill"},
+	}, quality)
+	_, err := structpb.NewStruct(map[string]interface{}{qualityAttribute: quality})
+	assert.Nil(t, err)
+}
+
+func TestGameMatchMakerQuality(t *testing.T) {
+	// prepare
+	now := time.Now()
+	rules := GameRules{
+		AllianceRule: AllianceRule{MinNumber: 2, MaxNumber: 2, PlayerMinNumber: 1, PlayerMaxNumber: 1},
+		SkillRule:    SkillRule{Attribute: "mmr", MaxSpread: 10},
+	}
+	tickets := []matchmaker.Ticket{newSkillTicket("a1", 10, now), newSkillTicket("b1", 13, now)}
+	before := qualityObservations(t, qualitySkillSpread, MatchLogicGame)
+
+	// act
+	matches := makeTestMatches(t, GameMatchMaker{}, rules, tickets)
+
+	// assert
+	if assert.Len(t, matches, 1) {
+		quality, ok := matches[0].MatchAttributes[qualityAttribute].(map[string]interface{})
+		if assert.True(t, ok) {
+			assert.Equal(t, 3.0, quality[qualitySkillSpread])
+			assert.Equal(t, 3.0, quality[qualityTeamBalanceDelta])
+			assert.Equal(t, []interface{}{}, quality[qualityRulesRelaxed])
+		}
+	}
+	assert.Equal(t, before+1, qualityObservations(t, qualitySkillSpread, MatchLogicGame))
+}
+
+func TestMatchMakerQuality(t *testing.T) {
+	// prepare
+	before := qualityObservations(t, qualityLongestWait, MatchLogicSimple)
+
+	// act
+	matches := makeTestMatches(t, MatchMaker{}, GameRules{}, []matchmaker.Ticket{newTestTicket("a1"), newTestTicket("b1")})
+
+	// assert
+	if assert.Len(t, matches, 1) {
+		assert.Contains(t, matches[0].MatchAttributes, qualityAttribute)
+	}
+	assert.Equal(t, before+1, qualityObservations(t, qualityLongestWait, MatchLogicSimple))
+}
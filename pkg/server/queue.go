@@ -0,0 +1,104 @@
+// Copyright (c) 2023 AccelByte Inc. All Rights Reserved.
+// This is licensed software from AccelByte Inc, for limitations
+// and restrictions contact your company contract manager.
+
+package server
+
+import (
+	"sync"
+
+	"matchmaking-function-grpc-plugin-server-go/pkg/matchmaker"
+)
+
+// queue holds the tickets of a bucket oldest first by CreatedAt, tickets created at the same time keep the order they
+// were pushed in. It is safe for concurrent use
+type queue struct {
+	tickets []matchmaker.Ticket
+	lock    sync.Mutex
+}
+
+func newQueue() *queue {
+	return &queue{tickets: make([]matchmaker.Ticket, 0)}
+}
+
+// push inserts the ticket after every ticket created no later than it
+func (q *queue) push(ticket matchmaker.Ticket) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	q.tickets = insertByAge(q.tickets, ticket)
+}
+
+// peek returns the oldest ticket that fits the filter without removing it, a nil filter takes the oldest ticket
+func (q *queue) peek(filter ticketFilter) *matchmaker.Ticket {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	for _, ticket := range q.tickets {
+		if filter == nil || filter.fits(ticket) {
+			return &ticket
+		}
+	}
+	return nil
+}
+
+// pop removes and returns the oldest ticket that fits the filter, a nil filter takes the oldest ticket
+func (q *queue) pop(filter ticketFilter) *matchmaker.Ticket {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	for i, ticket := range q.tickets {
+		if filter != nil && !filter.fits(ticket) {
+			continue
+		}
+		q.tickets = append(q.tickets[:i:i], q.tickets[i+1:]...)
+		return &ticket
+	}
+	return nil
+}
+
+// popNearest removes and returns the ticket fitting the filter at the smallest distance, the oldest of equally near
+// tickets
+func (q *queue) popNearest(filter ticketFilter, distance func(matchmaker.Ticket) float64) *matchmaker.Ticket {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	nearest, nearestDistance := -1, 0.0
+	for i, ticket := range q.tickets {
+		if filter != nil && !filter.fits(ticket) {
+			continue
+		}
+		if d := distance(ticket); nearest < 0 || d < nearestDistance {
+			nearest, nearestDistance = i, d
+		}
+	}
+	if nearest < 0 {
+		return nil
+	}
+	ticket := q.tickets[nearest]
+	q.tickets = append(q.tickets[:nearest:nearest], q.tickets[nearest+1:]...)
+	return &ticket
+}
+
+// count returns how many tickets fit the filter, without removing them
+func (q *queue) count(filter ticketFilter) int {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	count := 0
+	for _, ticket := range q.tickets {
+		if filter.fits(ticket) {
+			count++
+		}
+	}
+	return count
+}
+
+// snapshot returns a copy of the tickets, oldest first
+func (q *queue) snapshot() []matchmaker.Ticket {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	return append([]matchmaker.Ticket{}, q.tickets...)
+}
+
+// len returns the number of tickets in the queue
+func (q *queue) len() int {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	return len(q.tickets)
+}
@@ -0,0 +1,130 @@
+// Copyright (c) 2023 AccelByte Inc. All Rights Reserved.
+// This is licensed software from AccelByte Inc, for limitations
+// and restrictions contact your company contract manager.
+
+package server
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"matchmaking-function-grpc-plugin-server-go/pkg/matchmaker"
+)
+
+func TestQueueOrdersByCreatedAt(t *testing.T) {
+	// prepare
+	now := time.Now()
+	q := newQueue()
+	for _, ticket := range []struct {
+		id  string
+		age time.Duration
+	}{{"new", 0}, {"old", time.Minute}, {"mid-1", time.Second}, {"mid-2", time.Second}} {
+		q.push(matchmaker.Ticket{TicketID: ticket.id, CreatedAt: now.Add(-ticket.age)})
+	}
+
+	// act
+	var got []string
+	for ticket := q.pop(nil); ticket != nil; ticket = q.pop(nil) {
+		got = append(got, ticket.TicketID)
+	}
+
+	// assert
+	assert.Equal(t, []string{"old", "mid-1", "mid-2", "new"}, got)
+	assert.Equal(t, 0, q.len())
+}
+
+func TestQueuePopSkipsTicketsNotFitting(t *testing.T) {
+	// prepare
+	now := time.Now()
+	q := newQueue()
+	q.push(matchmaker.Ticket{TicketID: "old", CreatedAt: now.Add(-time.Minute)})
+	q.push(matchmaker.Ticket{TicketID: "new", CreatedAt: now})
+	tried := triedRoots{"old": true}
+
+	// act
+	ticket := q.pop(tried)
+	none := q.pop(tried)
+
+	// assert
+	if assert.NotNil(t, ticket) {
+		assert.Equal(t, "new", ticket.TicketID)
+	}
+	assert.Nil(t, none)
+	assert.Equal(t, 1, q.len())
+}
+
+func TestQueuePopNearest(t *testing.T) {
+	// prepare
+	now := time.Now()
+	q := newQueue()
+	for i, skill := range []float64{90, 5, 95, 5} {
+		q.push(newSkillTicket(fmt.Sprint("p", i), skill, now.Add(time.Duration(i)*time.Second)))
+	}
+	distance := skillDistance(SkillRule{Attribute: "mmr"}, newSkillTicket("seed", 0, now))
+
+	// act
+	first := q.popNearest(nil, distance)
+	second := q.popNearest(nil, distance)
+
+	// assert, equally near tickets leave oldest first
+	assert.Equal(t, "p1", string(first.Players[0].PlayerID))
+	assert.Equal(t, "p3", string(second.Players[0].PlayerID))
+	assert.Equal(t, 2, q.len())
+}
+
+func TestQueueConcurrentPushPop(t *testing.T) {
+	// prepare
+	const producers, perProducer = 8, 200
+	q := newQueue()
+	popped := make(chan string, producers*perProducer)
+	var pushing, popping sync.WaitGroup
+	done := make(chan struct{})
+
+	// act
+	for p := 0; p < producers; p++ {
+		pushing.Add(1)
+		go func(p int) {
+			defer pushing.Done()
+			for i := 0; i < perProducer; i++ {
+				q.push(matchmaker.Ticket{TicketID: fmt.Sprintf("%d-%d", p, i), CreatedAt: time.Now()})
+			}
+		}(p)
+	}
+	for c := 0; c < producers; c++ {
+		popping.Add(1)
+		go func() {
+			defer popping.Done()
+			for {
+				if ticket := q.pop(nil); ticket != nil {
+					popped <- ticket.TicketID
+					continue
+				}
+				select {
+				case <-done:
+					return
+				default:
+				}
+			}
+		}()
+	}
+	pushing.Wait()
+	for q.len() > 0 {
+		time.Sleep(time.Millisecond)
+	}
+	close(done)
+	popping.Wait()
+	close(popped)
+
+	// assert
+	seen := map[string]int{}
+	for id := range popped {
+		seen[id]++
+	}
+	assert.Len(t, seen, producers*perProducer)
+	for id, count := range seen {
+		assert.Equal(t, 1, count, id)
+	}
+}
@@ -0,0 +1,119 @@
+// Copyright (c) 2023 AccelByte Inc. All Rights Reserved.
+// This is licensed software from AccelByte Inc, for limitations
+// and restrictions contact your company contract manager.
+
+package server
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"matchmaking-function-grpc-plugin-server-go/pkg/matchmaker"
+)
+
+// enabled reports whether the rule limits latency at all
+func (r RegionRule) enabled() bool {
+	return r.MaxLatency > 0
+}
+
+// allowedLatency returns the latency limit for a match whose oldest ticket was created at oldest
+func (r RegionRule) allowedLatency(oldest time.Time, now time.Time) int64 {
+	if !r.enabled() {
+		return math.MaxInt64
+	}
+	latency := r.MaxLatency
+	if r.ExpansionStep > 0 && r.ExpansionIntervalSeconds > 0 {
+		steps := int64(now.Sub(oldest).Seconds()) / r.ExpansionIntervalSeconds
+		if steps > 0 {
+			latency += steps * r.ExpansionStep
+		}
+	}
+	if r.MaxExpandedLatency > 0 && latency > r.MaxExpandedLatency {
+		latency = r.MaxExpandedLatency
+	}
+	if latency < r.MaxLatency {
+		latency = r.MaxLatency
+	}
+	return latency
+}
+
+// matchRegions returns the regions every ticket reporting latencies can play in, ordered by their combined latency,
+// and whether the tickets may share a match under the rule. Tickets without latencies fit any region, and when no
+// ticket has latencies there is no preference at all. A ticket with an empty set of latencies fits no region
+func matchRegions(tickets []matchmaker.Ticket, rule RegionRule, now time.Time) ([]string, bool) {
+	if len(tickets) == 0 {
+		return nil, true
+	}
+	oldest := tickets[0].CreatedAt
+	for _, ticket := range tickets {
+		if ticket.CreatedAt.Before(oldest) {
+			oldest = ticket.CreatedAt
+		}
+	}
+	allowed := rule.allowedLatency(oldest, now)
+
+	var combined map[string]int64
+	for _, ticket := range tickets {
+		if ticket.Latencies == nil {
+			continue
+		}
+		if combined == nil {
+			combined = map[string]int64{}
+			for region, latency := range ticket.Latencies {
+				if latency <= allowed {
+					combined[region] = latency
+				}
+			}
+			continue
+		}
+		for region, total := range combined {
+			latency, ok := ticket.Latencies[region]
+			if !ok || latency > allowed {
+				delete(combined, region)
+				continue
+			}
+			combined[region] = total + latency
+		}
+	}
+	if combined == nil {
+		return nil, true
+	}
+
+	regions := make([]string, 0, len(combined))
+	for region := range combined {
+		regions = append(regions, region)
+	}
+	sort.Slice(regions, func(i, j int) bool {
+		if combined[regions[i]] != combined[regions[j]] {
+			return combined[regions[i]] < combined[regions[j]]
+		}
+		return regions[i] < regions[j]
+	})
+
+	return regions, !rule.enabled() || len(regions) > 0
+}
+
+// regionWindow is the ticketFilter keeping a viable region shared by all the tickets of a match
+type regionWindow struct {
+	rule    RegionRule
+	now     time.Time
+	tickets []matchmaker.Ticket
+}
+
+func newRegionWindow(rule RegionRule, seed matchmaker.Ticket, now time.Time) *regionWindow {
+	return &regionWindow{rule: rule, now: now, tickets: []matchmaker.Ticket{seed}}
+}
+
+func (w *regionWindow) fits(ticket matchmaker.Ticket) bool {
+	if !w.rule.enabled() {
+		return true
+	}
+	candidates := append(append(make([]matchmaker.Ticket, 0, len(w.tickets)+1), w.tickets...), ticket)
+	_, ok := matchRegions(candidates, w.rule, w.now)
+	return ok
+}
+
+func (w *regionWindow) add(ticket matchmaker.Ticket) {
+	w.tickets = append(w.tickets, ticket)
+}
@@ -0,0 +1,136 @@
+// Copyright (c) 2023 AccelByte Inc. All Rights Reserved.
+// This is licensed software from AccelByte Inc, for limitations
+// and restrictions contact your company contract manager.
+
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"matchmaking-function-grpc-plugin-server-go/pkg/matchmaker"
+	"matchmaking-function-grpc-plugin-server-go/pkg/player"
+)
+
+func newLatencyTicket(playerID string, latencies map[string]int64, createdAt time.Time) matchmaker.Ticket {
+	ticket := newTestTicket(playerID)
+	ticket.CreatedAt = createdAt
+	ticket.Latencies = latencies
+	return ticket
+}
+
+func TestMatchRegions(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name        string
+		rule        RegionRule
+		tickets     []matchmaker.Ticket
+		wantRegions []string
+		wantOK      bool
+	}{
+		{
+			name: "ordered by combined latency",
+			rule: RegionRule{MaxLatency: 100},
+			tickets: []matchmaker.Ticket{
+				newLatencyTicket("a", map[string]int64{"us-east-1": 20, "eu-west-1": 90, "ap-south-1": 300}, now),
+				newLatencyTicket("b", map[string]int64{"us-east-1": 80, "eu-west-1": 5}, now),
+			},
+			wantRegions: []string{"eu-west-1", "us-east-1"},
+			wantOK:      true,
+		},
+		{
+			name: "no shared viable region",
+			rule: RegionRule{MaxLatency: 50},
+			tickets: []matchmaker.Ticket{
+				newLatencyTicket("a", map[string]int64{"us-east-1": 20, "eu-west-1": 90}, now),
+				newLatencyTicket("b", map[string]int64{"us-east-1": 80, "eu-west-1": 5}, now),
+			},
+			wantRegions: []string{},
+			wantOK:      false,
+		},
+		{
+			name: "limit expands with ticket age",
+			rule: RegionRule{MaxLatency: 50, ExpansionStep: 20, ExpansionIntervalSeconds: 10, MaxExpandedLatency: 200},
+			tickets: []matchmaker.Ticket{
+				newLatencyTicket("a", map[string]int64{"us-east-1": 20, "eu-west-1": 90}, now.Add(-25*time.Second)),
+				newLatencyTicket("b", map[string]int64{"us-east-1": 80, "eu-west-1": 5}, now),
+			},
+			wantRegions: []string{"eu-west-1", "us-east-1"},
+			wantOK:      true,
+		},
+		{
+			name: "tickets without latencies fit anywhere",
+			rule: RegionRule{MaxLatency: 50},
+			tickets: []matchmaker.Ticket{
+				newLatencyTicket("a", map[string]int64{"us-east-1": 20}, now),
+				newLatencyTicket("b", nil, now),
+			},
+			wantRegions: []string{"us-east-1"},
+			wantOK:      true,
+		},
+		{
+			name:        "no latencies at all",
+			rule:        RegionRule{MaxLatency: 50},
+			tickets:     []matchmaker.Ticket{newLatencyTicket("a", nil, now), newLatencyTicket("b", nil, now)},
+			wantRegions: nil,
+			wantOK:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// act
+			regions, ok := matchRegions(tt.tickets, tt.rule, now)
+
+			// assert
+			assert.Equal(t, tt.wantRegions, regions)
+			assert.Equal(t, tt.wantOK, ok)
+		})
+	}
+}
+
+func TestGameMatchMakerRegion(t *testing.T) {
+	// prepare
+	now := time.Now()
+	rules := GameRules{
+		AllianceRule: AllianceRule{PlayerMinNumber: 2, PlayerMaxNumber: 2},
+		RegionRule:   RegionRule{MaxLatency: 100},
+	}
+	tickets := []matchmaker.Ticket{
+		newLatencyTicket("us1", map[string]int64{"us-east-1": 30, "eu-west-1": 150}, now),
+		newLatencyTicket("eu1", map[string]int64{"us-east-1": 150, "eu-west-1": 30}, now),
+		newLatencyTicket("us2", map[string]int64{"us-east-1": 40, "eu-west-1": 90}, now),
+		newLatencyTicket("eu2", map[string]int64{"us-east-1": 160, "eu-west-1": 20}, now),
+	}
+
+	// act
+	matches := makeTestMatches(t, GameMatchMaker{}, rules, tickets)
+
+	// assert
+	if assert.Len(t, matches, 2) {
+		assert.Equal(t, []string{"us-east-1"}, matches[0].RegionPreference)
+		assert.ElementsMatch(t, []player.ID{"us1", "us2"}, matches[0].Teams[0].UserIDs)
+		assert.Equal(t, []string{"eu-west-1"}, matches[1].RegionPreference)
+		assert.ElementsMatch(t, []player.ID{"eu1", "eu2"}, matches[1].Teams[0].UserIDs)
+	}
+}
+
+func TestMatchMakerRegion(t *testing.T) {
+	// prepare
+	now := time.Now()
+	rules := GameRules{RegionRule: RegionRule{MaxLatency: 100}}
+	tickets := []matchmaker.Ticket{
+		newLatencyTicket("us1", map[string]int64{"us-east-1": 30}, now),
+		newLatencyTicket("eu1", map[string]int64{"eu-west-1": 30}, now),
+		newLatencyTicket("us2", map[string]int64{"us-east-1": 40}, now),
+	}
+
+	// act
+	matches := makeTestMatches(t, New(), rules, tickets)
+
+	// assert
+	if assert.Len(t, matches, 1) {
+		assert.Equal(t, []string{"us-east-1"}, matches[0].RegionPreference)
+		assert.ElementsMatch(t, []player.ID{"us1", "us2"}, matches[0].Teams[0].UserIDs)
+	}
+}
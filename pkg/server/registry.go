@@ -0,0 +1,142 @@
+// Copyright (c) 2023 AccelByte Inc. All Rights Reserved.
+// This is licensed software from AccelByte Inc, for limitations
+// and restrictions contact your company contract manager.
+
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Names the match logics are registered under in the default registry
+const (
+	MatchLogicSimple = "simple"
+	MatchLogicGame   = "game"
+	MatchLogicCrew   = "crew"
+)
+
+// Registry holds the match logics one deployment serves. A call is handled by the logic named in the "match_logic"
+// field of its rules json, else by the logic assigned to the match pool of its tickets, else by the default logic
+type Registry struct {
+	lock        sync.RWMutex
+	logics      map[string]MatchLogic
+	pools       map[string]string
+	defaultName string
+}
+
+// NewRegistry returns an empty registry falling back to the logic registered as defaultName
+func NewRegistry(defaultName string) *Registry {
+	return &Registry{
+		logics:      map[string]MatchLogic{},
+		pools:       map[string]string{},
+		defaultName: defaultName,
+	}
+}
+
+// NewDefaultRegistry returns a registry with every match logic of this server, GameMatchMaker being the default
+func NewDefaultRegistry() *Registry {
+	registry := NewRegistry(MatchLogicGame)
+	registry.Register(MatchLogicSimple, New())
+	registry.Register(MatchLogicGame, NewGameMatchmaker())
+	registry.Register(MatchLogicCrew, NewCrewMatchmaker())
+	return registry
+}
+
+// Register adds the logic under name, replacing any logic registered under the same name
+func (r *Registry) Register(name string, logic MatchLogic) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.logics[name] = logic
+}
+
+// SetDefault changes the logic used when neither the rules nor the match pool pick one
+func (r *Registry) SetDefault(name string) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if _, ok := r.logics[name]; !ok {
+		return fmt.Errorf("unknown match logic %q", name)
+	}
+	r.defaultName = name
+	return nil
+}
+
+// AssignPool routes the calls for matchPool to the logic registered as name
+func (r *Registry) AssignPool(matchPool string, name string) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if _, ok := r.logics[name]; !ok {
+		return fmt.Errorf("unknown match logic %q for match pool %q", name, matchPool)
+	}
+	r.pools[matchPool] = name
+	return nil
+}
+
+// AssignPools parses a comma separated list of pool:logic pairs, e.g. "ranked:game,casual:simple"
+func (r *Registry) AssignPools(assignments string) error {
+	for _, assignment := range strings.Split(assignments, ",") {
+		assignment = strings.TrimSpace(assignment)
+		if assignment == "" {
+			continue
+		}
+		matchPool, name, ok := strings.Cut(assignment, ":")
+		if !ok {
+			return fmt.Errorf("match pool assignment %q is not in the pool:logic format", assignment)
+		}
+		if err := r.AssignPool(strings.TrimSpace(matchPool), strings.TrimSpace(name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NeedsPool reports whether the match pool is still needed to pick a logic once the rules json is known
+func (r *Registry) NeedsPool(rulesJSON string) bool {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	return logicNameFromJSON(rulesJSON) == "" && len(r.pools) > 0
+}
+
+// Lookup returns the logic handling a call with the given rules json and match pool
+func (r *Registry) Lookup(rulesJSON string, matchPool string) (MatchLogic, error) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	name := logicNameFromJSON(rulesJSON)
+	if name == "" {
+		name = r.pools[matchPool]
+	}
+	if name == "" {
+		name = r.defaultName
+	}
+	logic, ok := r.logics[name]
+	if !ok {
+		return nil, invalidArgument("rules", "match_logic", "names the unknown match logic %q", name)
+	}
+	return logic, nil
+}
+
+// logicNameFromJSON reads the match_logic field of the rules, rules that do not decode name no logic and are left
+// for the logic's own RulesFromJSON to reject
+func logicNameFromJSON(rulesJSON string) string {
+	var rules struct {
+		MatchLogic string `json:"match_logic"`
+	}
+	if err := json.Unmarshal([]byte(rulesJSON), &rules); err != nil {
+		return ""
+	}
+	return rules.MatchLogic
+}
+
+// statCodesFromJSON reads the stat_codes field of the rules, the fields of the rules every logic shares, leaving the
+// rest of the rules unchecked
+func statCodesFromJSON(rulesJSON string) ([]string, error) {
+	var rules struct {
+		StatCodes []string `json:"stat_codes"`
+	}
+	if err := json.Unmarshal([]byte(rulesJSON), &rules); err != nil {
+		return nil, invalidRules("stat_codes", "could not be read: %s", err)
+	}
+	return GameRules{StatCodes: rules.StatCodes}.statCodes(), nil
+}
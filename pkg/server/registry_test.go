@@ -0,0 +1,119 @@
+// Copyright (c) 2023 AccelByte Inc. All Rights Reserved.
+// This is licensed software from AccelByte Inc, for limitations
+// and restrictions contact your company contract manager.
+
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"matchmaking-function-grpc-plugin-server-go/pkg/matchmaker"
+	matchfunctiongrpc "matchmaking-function-grpc-plugin-server-go/pkg/pb"
+)
+
+func TestRegistryLookup(t *testing.T) {
+	// prepare
+	registry := NewDefaultRegistry()
+	err := registry.AssignPools("casual:simple, ranked:game")
+	assert.Nil(t, err)
+
+	tests := []struct {
+		name      string
+		rulesJSON string
+		matchPool string
+		want      MatchLogic
+		wantErr   bool
+	}{
+		{name: "rules pick the logic", rulesJSON: `{"match_logic":"simple"}`, matchPool: "ranked", want: MatchMaker{}},
+		{name: "match pool picks the logic", rulesJSON: `{}`, matchPool: "casual", want: MatchMaker{}},
+		{name: "default logic", rulesJSON: `{}`, matchPool: "other", want: GameMatchMaker{}},
+		{name: "crew logic", rulesJSON: `{"match_logic":"crew"}`, matchPool: "ranked", want: CrewMatchMaker{}},
+		{name: "rules that do not decode", rulesJSON: `not json`, matchPool: "casual", want: MatchMaker{}},
+		{name: "unknown logic", rulesJSON: `{"match_logic":"nope"}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// act
+			got, err := registry.Lookup(tt.rulesJSON, tt.matchPool)
+
+			// assert
+			if tt.wantErr {
+				assert.NotNil(t, err)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRegistryAssignPoolsErrors(t *testing.T) {
+	registry := NewDefaultRegistry()
+
+	assert.NotNil(t, registry.AssignPools("casual"))
+	assert.NotNil(t, registry.AssignPools("casual:nope"))
+	assert.NotNil(t, registry.SetDefault("nope"))
+	assert.False(t, registry.NeedsPool(`{}`))
+	assert.Nil(t, registry.AssignPools(""))
+}
+
+func TestMakeMatchesRoutedByMatchPool(t *testing.T) {
+	// prepare
+	registry := NewDefaultRegistry()
+	assert.Nil(t, registry.AssignPools("casual:simple"))
+	server := MatchFunctionServer{
+		UnimplementedMatchFunctionServer: matchfunctiongrpc.UnimplementedMatchFunctionServer{},
+		Registry:                         registry,
+	}
+	stream := &fakeMakeMatchesStream{ctx: context.Background(), requests: make(chan *matchfunctiongrpc.MakeMatchesRequest, 3)}
+	stream.requests <- &matchfunctiongrpc.MakeMatchesRequest{
+		RequestType: &matchfunctiongrpc.MakeMatchesRequest_Parameters{
+			Parameters: &matchfunctiongrpc.MakeMatchesRequest_MakeMatchesParameters{
+				Rules: &matchfunctiongrpc.Rules{Json: `{}`},
+			},
+		},
+	}
+	for _, ticket := range []matchmaker.Ticket{newTestTicket("a1"), newTestTicket("b1")} {
+		ticket.MatchPool = "casual"
+		stream.requests <- &matchfunctiongrpc.MakeMatchesRequest{
+			RequestType: &matchfunctiongrpc.MakeMatchesRequest_Ticket{Ticket: matchfunctiongrpc.MatchfunctionTicketToProtoTicket(ticket)},
+		}
+	}
+	close(stream.requests)
+
+	// act
+	err := server.MakeMatches(stream)
+
+	// assert, the default game logic would make no match with all zero alliance rules
+	assert.Nil(t, err)
+	if assert.Equal(t, 1, stream.matchesSent()) {
+		assert.Equal(t, []string{"a1", "b1"}, stream.responses[0].Match.Teams[0].UserIds)
+	}
+}
+
+func TestGetStatCodesRoutedByMatchPool(t *testing.T) {
+	// prepare
+	registry := NewDefaultRegistry()
+	assert.Nil(t, registry.AssignPools("casual:simple"))
+	server := MatchFunctionServer{
+		UnimplementedMatchFunctionServer: matchfunctiongrpc.UnimplementedMatchFunctionServer{},
+		Registry:                         registry,
+	}
+
+	// act
+	resp, err := server.GetStatCodes(context.Background(), &matchfunctiongrpc.GetStatCodesRequest{
+		Rules: &matchfunctiongrpc.Rules{Json: `{"stat_codes":["mmr","kills","mmr"]}`},
+	})
+	_, malformedErr := server.GetStatCodes(context.Background(), &matchfunctiongrpc.GetStatCodesRequest{
+		Rules: &matchfunctiongrpc.Rules{Json: `{"stat_codes":"mmr"}`},
+	})
+
+	// assert, the default game logic would reject the rules for their empty alliance rule
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"mmr", "kills"}, resp.GetCodes())
+	assert.Equal(t, codes.InvalidArgument, status.Code(malformedErr))
+}
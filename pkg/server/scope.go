@@ -0,0 +1,57 @@
+// Copyright (c) 2023 AccelByte Inc. All Rights Reserved.
+// This is licensed software from AccelByte Inc, for limitations
+// and restrictions contact your company contract manager.
+
+package server
+
+import (
+	"context"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Scope is handed to the match logic for the lifetime of a single streaming call. Ctx is cancelled when the stream
+// ends, and Log tags every entry with the trace id the platform sent in the call parameters. Caller holds the
+// validated claims of the platform client making the call, nil when auth is disabled
+type Scope struct {
+	Ctx     context.Context
+	TraceID string
+	Log     *logrus.Entry
+	Caller  *JWTClaims
+
+	rejectedMu sync.Mutex
+	rejected   map[[2]string]bool
+}
+
+// NewScope returns a Scope bound to ctx and traceID, taking the caller from ctx
+func NewScope(ctx context.Context, traceID string) *Scope {
+	scope := &Scope{
+		Ctx:     ctx,
+		TraceID: traceID,
+		Log:     logrus.WithField("traceID", traceID),
+	}
+	if claims, ok := CallerFromContext(ctx); ok {
+		scope.Caller = claims
+		scope.Log = scope.Log.WithField("clientID", claims.ClientID)
+	}
+	return scope
+}
+
+// rejectPairing counts the pairing of the tickets with the given IDs as rejected for the reason, once per call however
+// often the pairing is refused
+func (s *Scope) rejectPairing(reason string, a string, b string) {
+	if b < a {
+		a, b = b, a
+	}
+	s.rejectedMu.Lock()
+	defer s.rejectedMu.Unlock()
+	if s.rejected[[2]string{a, b}] {
+		return
+	}
+	if s.rejected == nil {
+		s.rejected = map[[2]string]bool{}
+	}
+	s.rejected[[2]string{a, b}] = true
+	rejectedPairings.WithLabelValues(reason).Inc()
+}
@@ -0,0 +1,173 @@
+// Copyright (c) 2023 AccelByte Inc. All Rights Reserved.
+// This is licensed software from AccelByte Inc, for limitations
+// and restrictions contact your company contract manager.
+
+package server
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"matchmaking-function-grpc-plugin-server-go/pkg/matchmaker"
+)
+
+// enabled reports whether the rule names an attribute to match on
+func (r SkillRule) enabled() bool {
+	return r.Attribute != ""
+}
+
+// allowedSpread returns the spread accepted for a match whose oldest ticket was created at oldest
+func (r SkillRule) allowedSpread(oldest time.Time, now time.Time) float64 {
+	spread := r.MaxSpread
+	if waited := now.Sub(oldest).Seconds(); waited > 0 {
+		spread += r.SpreadPerSecond * waited
+	}
+	if r.MaxWidenedSpread > 0 && spread > r.MaxWidenedSpread {
+		spread = math.Max(r.MaxSpread, r.MaxWidenedSpread)
+	}
+	return spread
+}
+
+// ticketSkill returns the average of the attribute over the players of the ticket. Players without it are left out,
+// and when no player has it the ticket attribute of the same name is used instead
+func ticketSkill(ticket matchmaker.Ticket, attribute string) float64 {
+	total, count := 0.0, 0
+	for _, p := range ticket.Players {
+		if value, ok := numberAttribute(p.Attributes, attribute); ok {
+			total += value
+			count++
+		}
+	}
+	if count > 0 {
+		return total / float64(count)
+	}
+	value, _ := numberAttribute(ticket.TicketAttributes, attribute)
+	return value
+}
+
+// hasSkill reports whether a player of the ticket, or the ticket itself, carries the attribute
+func hasSkill(ticket matchmaker.Ticket, attribute string) bool {
+	for _, p := range ticket.Players {
+		if _, ok := numberAttribute(p.Attributes, attribute); ok {
+			return true
+		}
+	}
+	_, ok := numberAttribute(ticket.TicketAttributes, attribute)
+	return ok
+}
+
+// numberAttribute reads a numeric attribute, decoded structs hold every number as a float64
+func numberAttribute(attributes map[string]interface{}, name string) (float64, bool) {
+	return numberValue(attributes[name])
+}
+
+// numberValue reads a numeric value of any of the types attributes hold numbers in
+func numberValue(value interface{}) (float64, bool) {
+	switch value := value.(type) {
+	case float64:
+		return value, true
+	case int:
+		return float64(value), true
+	case int64:
+		return float64(value), true
+	default:
+		return 0, false
+	}
+}
+
+// skillDistance returns how far the skill of a ticket is from the seed's, so the closest match joins first. Without a
+// skill attribute every ticket is as close as any other
+func skillDistance(rule SkillRule, seed matchmaker.Ticket) func(matchmaker.Ticket) float64 {
+	if !rule.enabled() {
+		return func(matchmaker.Ticket) float64 { return 0 }
+	}
+	seedSkill := ticketSkill(seed, rule.Attribute)
+	return func(ticket matchmaker.Ticket) float64 {
+		return math.Abs(ticketSkill(ticket, rule.Attribute) - seedSkill)
+	}
+}
+
+// skillWindow is the ticketFilter keeping the skill range of a match within the rule's spread. Tickets without the
+// skill attribute share no match under the rule
+type skillWindow struct {
+	rule    SkillRule
+	now     time.Time
+	oldest  time.Time
+	low     float64
+	high    float64
+	unrated bool
+}
+
+func newSkillWindow(rule SkillRule, seed matchmaker.Ticket, now time.Time) *skillWindow {
+	skill := ticketSkill(seed, rule.Attribute)
+	unrated := rule.enabled() && !hasSkill(seed, rule.Attribute)
+	return &skillWindow{rule: rule, now: now, oldest: seed.CreatedAt, low: skill, high: skill, unrated: unrated}
+}
+
+func (w *skillWindow) fits(ticket matchmaker.Ticket) bool {
+	if !w.rule.enabled() {
+		return true
+	}
+	if w.unrated || !hasSkill(ticket, w.rule.Attribute) {
+		return false
+	}
+	oldest := w.oldest
+	if ticket.CreatedAt.Before(oldest) {
+		oldest = ticket.CreatedAt
+	}
+	skill := ticketSkill(ticket, w.rule.Attribute)
+	return math.Max(w.high, skill)-math.Min(w.low, skill) <= w.rule.allowedSpread(oldest, w.now)
+}
+
+func (w *skillWindow) add(ticket matchmaker.Ticket) {
+	skill := ticketSkill(ticket, w.rule.Attribute)
+	w.low = math.Min(w.low, skill)
+	w.high = math.Max(w.high, skill)
+	if ticket.CreatedAt.Before(w.oldest) {
+		w.oldest = ticket.CreatedAt
+	}
+}
+
+// balanceTeams deals the tickets of a match out again, strongest first, each to the team with the lowest total skill
+// that still has room for the whole ticket. The original teams are kept when the deal cannot seat every ticket or
+// leaves a team below playerMin
+func balanceTeams(teams [][]matchmaker.Ticket, rule SkillRule, playerMin int, playerMax int) [][]matchmaker.Ticket {
+	if !rule.enabled() || len(teams) < 2 {
+		return teams
+	}
+	var tickets []matchmaker.Ticket
+	for _, team := range teams {
+		tickets = append(tickets, team...)
+	}
+	sort.SliceStable(tickets, func(i, j int) bool {
+		return ticketSkill(tickets[i], rule.Attribute) > ticketSkill(tickets[j], rule.Attribute)
+	})
+
+	balanced := make([][]matchmaker.Ticket, len(teams))
+	playerCounts := make([]int, len(teams))
+	totals := make([]float64, len(teams))
+	for _, ticket := range tickets {
+		team := -1
+		for i := range balanced {
+			if playerCounts[i]+len(ticket.Players) > playerMax {
+				continue
+			}
+			if team < 0 || totals[i] < totals[team] {
+				team = i
+			}
+		}
+		if team < 0 {
+			return teams
+		}
+		balanced[team] = append(balanced[team], ticket)
+		playerCounts[team] += len(ticket.Players)
+		totals[team] += ticketSkill(ticket, rule.Attribute) * float64(len(ticket.Players))
+	}
+	for _, count := range playerCounts {
+		if count < playerMin || count == 0 {
+			return teams
+		}
+	}
+	return balanced
+}
@@ -0,0 +1,241 @@
+// Copyright (c) 2023 AccelByte Inc. All Rights Reserved.
+// This is licensed software from AccelByte Inc, for limitations
+// and restrictions contact your company contract manager.
+
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var rangeTag = regexp.MustCompile(`^range\(([^|]+)\|([^)]+)\)$`)
+
+// invalidRules returns the InvalidArgument error naming the offending field of a rule set
+func invalidRules(field string, format string, args ...interface{}) error {
+	return invalidArgument("rules", field, format, args...)
+}
+
+// decodeRules decodes the rules json into rules, rejecting fields the rules do not know about
+func decodeRules(jsonRules string, rules interface{}) error {
+	decoder := json.NewDecoder(bytes.NewBufferString(jsonRules))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(rules); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		switch {
+		case errors.As(err, &typeErr):
+			return invalidRules(typeErr.Field, "must be a %s, got a %s", typeErr.Type, typeErr.Value)
+		case strings.HasPrefix(err.Error(), "json: unknown field "):
+			return invalidRules(strings.TrimPrefix(err.Error(), "json: unknown field "), "is not a known field")
+		case err == io.EOF:
+			return invalidRules("", "the rules json is empty")
+		default:
+			return invalidRules("", "%s", err)
+		}
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return invalidRules("", "unexpected data after the rules json")
+	}
+	return nil
+}
+
+// validateRanges checks every numeric field carrying a valid:"range(min|max)" tag, descending into nested structs and
+// slices of them. Fields are named by their json path, e.g. priority.relaxations[0].max_spread
+func validateRanges(value interface{}) error {
+	return validateStructRanges(reflect.ValueOf(value), "")
+}
+
+func validateStructRanges(value reflect.Value, path string) error {
+	if value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return nil
+		}
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return nil
+	}
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "" {
+			name = field.Name
+		}
+		if path != "" {
+			name = path + "." + name
+		}
+
+		fieldValue := value.Field(i)
+		if fieldValue.Kind() == reflect.Struct || fieldValue.Kind() == reflect.Ptr {
+			if err := validateStructRanges(fieldValue, name); err != nil {
+				return err
+			}
+			continue
+		}
+		if fieldValue.Kind() == reflect.Slice {
+			for j := 0; j < fieldValue.Len(); j++ {
+				if err := validateStructRanges(fieldValue.Index(j), fmt.Sprintf("%s[%d]", name, j)); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		match := rangeTag.FindStringSubmatch(field.Tag.Get("valid"))
+		if match == nil {
+			continue
+		}
+		var number float64
+		switch fieldValue.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			number = float64(fieldValue.Int())
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			number = float64(fieldValue.Uint())
+		case reflect.Float32, reflect.Float64:
+			number = fieldValue.Float()
+		default:
+			continue
+		}
+		min, minErr := strconv.ParseFloat(match[1], 64)
+		max, maxErr := strconv.ParseFloat(match[2], 64)
+		if minErr != nil || maxErr != nil {
+			return fmt.Errorf("malformed range tag on %s", name)
+		}
+		if number < min || number > max {
+			return invalidRules(name, "must be between %s and %s, got %v", match[1], match[2], number)
+		}
+	}
+	return nil
+}
+
+// Validate checks the ranges of the rules and that related fields agree with each other
+func (r GameRules) Validate() error {
+	if err := validateRanges(r); err != nil {
+		return err
+	}
+
+	alliance := r.AllianceRule
+	if alliance.MaxNumber > 0 && alliance.MinNumber > alliance.MaxNumber {
+		return invalidRules("alliance.min_number", "must not be greater than alliance.max_number (%d), got %d", alliance.MaxNumber, alliance.MinNumber)
+	}
+	if alliance.PlayerMinNumber > alliance.PlayerMaxNumber {
+		return invalidRules("alliance.player_min_number", "must not be greater than alliance.player_max_number (%d), got %d", alliance.PlayerMaxNumber, alliance.PlayerMinNumber)
+	}
+
+	skill := r.SkillRule
+	if !skill.enabled() && (skill.MaxSpread > 0 || skill.SpreadPerSecond > 0 || skill.MaxWidenedSpread > 0) {
+		return invalidRules("skill.attribute", "is required when a skill spread is set")
+	}
+	if skill.MaxWidenedSpread > 0 && skill.MaxWidenedSpread < skill.MaxSpread {
+		return invalidRules("skill.max_widened_spread", "must not be less than skill.max_spread (%v), got %v", skill.MaxSpread, skill.MaxWidenedSpread)
+	}
+
+	region := r.RegionRule
+	if !region.enabled() && (region.ExpansionStep > 0 || region.MaxExpandedLatency > 0) {
+		return invalidRules("region.max_latency", "is required when the region expands")
+	}
+	if region.ExpansionStep > 0 && region.ExpansionIntervalSeconds == 0 {
+		return invalidRules("region.expansion_interval_seconds", "is required when region.expansion_step is set")
+	}
+	if region.MaxExpandedLatency > 0 && region.MaxExpandedLatency < region.MaxLatency {
+		return invalidRules("region.max_expanded_latency", "must not be less than region.max_latency (%d), got %d", region.MaxLatency, region.MaxExpandedLatency)
+	}
+
+	for i, relaxation := range r.PriorityRule.Relaxations {
+		field := fmt.Sprintf("priority.relaxations[%d]", i)
+		if i > 0 && relaxation.AfterSeconds <= r.PriorityRule.Relaxations[i-1].AfterSeconds {
+			return invalidRules(field+".after_seconds", "must be greater than the previous step's (%d), got %d", r.PriorityRule.Relaxations[i-1].AfterSeconds, relaxation.AfterSeconds)
+		}
+		if relaxation.MaxSpread > 0 && !skill.enabled() {
+			return invalidRules("skill.attribute", "is required when %s.max_spread is set", field)
+		}
+		if relaxation.MaxLatency > 0 && !region.enabled() {
+			return invalidRules("region.max_latency", "is required when %s.max_latency is set", field)
+		}
+	}
+
+	sizes := map[int]bool{}
+	for i, limit := range r.PartyRule.Limits {
+		if sizes[limit.Size] {
+			return invalidRules(fmt.Sprintf("party.limits[%d].size", i), "must not repeat the size of another limit, got %d", limit.Size)
+		}
+		sizes[limit.Size] = true
+	}
+
+	composition := r.CompositionRule
+	if composition.enabled() && composition.Attribute == "" {
+		return invalidRules("composition.attribute", "is required when role quotas are set")
+	}
+	roles := map[string]bool{}
+	roleMinimum := 0
+	for i, quota := range composition.Roles {
+		field := fmt.Sprintf("composition.roles[%d]", i)
+		if quota.Role == "" || roles[quota.Role] {
+			return invalidRules(field+".role", "must be set and unique, got %q", quota.Role)
+		}
+		roles[quota.Role] = true
+		if quota.Min > quota.Max {
+			return invalidRules(field+".min", "must not be greater than %s.max (%d), got %d", field, quota.Max, quota.Min)
+		}
+		roleMinimum += quota.Min
+	}
+	if alliance.PlayerMaxNumber > 0 && roleMinimum > alliance.PlayerMaxNumber {
+		return invalidRules("composition.roles", "need at least %d players per team, more than alliance.player_max_number (%d)", roleMinimum, alliance.PlayerMaxNumber)
+	}
+
+	for i, rule := range r.AttributeRules {
+		field := fmt.Sprintf("attribute_rules[%d]", i)
+		if rule.Attribute == "" {
+			return invalidRules(field+".attribute", "must not be empty")
+		}
+		switch rule.Type {
+		case AttributeEqual, AttributeIntersect, AttributeDistance:
+		default:
+			return invalidRules(field+".type", "must be one of %s, %s or %s, got %q", AttributeEqual, AttributeIntersect, AttributeDistance, rule.Type)
+		}
+		switch rule.Source {
+		case "", AttributeSourceTicket, AttributeSourcePlayer:
+		default:
+			return invalidRules(field+".source", "must be %s or %s, got %q", AttributeSourceTicket, AttributeSourcePlayer, rule.Source)
+		}
+		if rule.MaxDistance > 0 && rule.Type != AttributeDistance {
+			return invalidRules(field+".max_distance", "only applies to the %s type", AttributeDistance)
+		}
+	}
+
+	for i, versions := range r.VersionRule.Compatible {
+		field := fmt.Sprintf("client_version.compatible[%d]", i)
+		if versions.Min == "" || versions.Max == "" {
+			return invalidRules(field, "must set both min and max")
+		}
+		if compareVersions(versions.Min, versions.Max) > 0 {
+			return invalidRules(field+".min", "must not be newer than %s.max (%s), got %s", field, versions.Max, versions.Min)
+		}
+	}
+
+	for i, code := range r.StatCodes {
+		if code == "" {
+			return invalidRules(fmt.Sprintf("stat_codes[%d]", i), "must not be empty")
+		}
+	}
+	return nil
+}
+
+// validateTeams additionally requires the alliance rule to seat at least one player per team, for the logics that
+// build teams from it
+func (r GameRules) validateTeams() error {
+	if r.AllianceRule.PlayerMaxNumber < 1 {
+		return invalidRules("alliance.player_max_number", "must be at least 1, got %d", r.AllianceRule.PlayerMaxNumber)
+	}
+	return nil
+}
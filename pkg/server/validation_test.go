@@ -0,0 +1,109 @@
+// Copyright (c) 2023 AccelByte Inc. All Rights Reserved.
+// This is licensed software from AccelByte Inc, for limitations
+// and restrictions contact your company contract manager.
+
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	matchfunctiongrpc "matchmaking-function-grpc-plugin-server-go/pkg/pb"
+)
+
+func TestGameRulesFromJSON(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules string
+		field string
+	}{
+		{name: "valid", rules: `{"alliance":{"min_number":2,"max_number":2,"player_min_number":1,"player_max_number":4}}`},
+		{name: "unknown field", rules: `{"alliance":{"player_max_number":4},"teamCount":2}`, field: `"teamCount"`},
+		{name: "ship counts", rules: `{"alliance":{"player_max_number":4},"shipCountMin":2,"shipCountMax":2}`},
+		{name: "negative ship count", rules: `{"alliance":{"player_max_number":4},"shipCountMin":-2}`, field: "shipCountMin"},
+		{name: "wrong type", rules: `{"alliance":{"player_max_number":"four"}}`, field: "alliance.player_max_number"},
+		{name: "negative", rules: `{"alliance":{"min_number":-1,"player_max_number":4}}`, field: "alliance.min_number"},
+		{name: "all zero", rules: `{}`, field: "alliance.player_max_number"},
+		{name: "min above max", rules: `{"alliance":{"min_number":3,"max_number":2,"player_max_number":4}}`, field: "alliance.min_number"},
+		{name: "player min above max", rules: `{"alliance":{"player_min_number":5,"player_max_number":4}}`, field: "alliance.player_min_number"},
+		{name: "spread without attribute", rules: `{"alliance":{"player_max_number":4},"skill":{"max_spread":10}}`, field: "skill.attribute"},
+		{name: "widened below spread", rules: `{"alliance":{"player_max_number":4},"skill":{"attribute":"mmr","max_spread":10,"max_widened_spread":5}}`, field: "skill.max_widened_spread"},
+		{name: "step without interval", rules: `{"alliance":{"player_max_number":4},"region":{"max_latency":100,"expansion_step":50}}`, field: "region.expansion_interval_seconds"},
+		{name: "expanded below latency", rules: `{"alliance":{"player_max_number":4},"region":{"max_latency":100,"max_expanded_latency":50}}`, field: "region.max_expanded_latency"},
+		{name: "empty stat code", rules: `{"alliance":{"player_max_number":4},"stat_codes":["mmr",""]}`, field: "stat_codes[1]"},
+		{name: "relaxation schedule", rules: `{"alliance":{"player_min_number":4,"player_max_number":4},"priority":{"oldest_first":true,"relaxations":[{"after_seconds":30,"player_min_number":3},{"after_seconds":60,"player_min_number":2}]}}`},
+		{name: "negative relaxation", rules: `{"alliance":{"player_max_number":4},"priority":{"relaxations":[{"after_seconds":-1}]}}`, field: "priority.relaxations[0].after_seconds"},
+		{name: "relaxations out of order", rules: `{"alliance":{"player_max_number":4},"priority":{"relaxations":[{"after_seconds":60},{"after_seconds":30}]}}`, field: "priority.relaxations[1].after_seconds"},
+		{name: "relaxed spread without attribute", rules: `{"alliance":{"player_max_number":4},"priority":{"relaxations":[{"max_spread":10}]}}`, field: "skill.attribute"},
+		{name: "relaxed latency without region", rules: `{"alliance":{"player_max_number":4},"priority":{"relaxations":[{"max_latency":100}]}}`, field: "region.max_latency"},
+		{name: "party limits", rules: `{"alliance":{"player_max_number":4},"party":{"limits":[{"size":2,"max_per_team":1},{"size":3,"max_per_team":0}]}}`},
+		{name: "party limit without size", rules: `{"alliance":{"player_max_number":4},"party":{"limits":[{"max_per_team":1}]}}`, field: "party.limits[0].size"},
+		{name: "repeated party size", rules: `{"alliance":{"player_max_number":4},"party":{"limits":[{"size":2,"max_per_team":1},{"size":2,"max_per_team":2}]}}`, field: "party.limits[1].size"},
+		{name: "composition", rules: `{"alliance":{"player_max_number":5},"composition":{"attribute":"class","roles":[{"role":"tank","min":1,"max":1},{"role":"damage","min":3,"max":4}]}}`},
+		{name: "roles without attribute", rules: `{"alliance":{"player_max_number":5},"composition":{"roles":[{"role":"tank","min":1,"max":1}]}}`, field: "composition.attribute"},
+		{name: "repeated role", rules: `{"alliance":{"player_max_number":5},"composition":{"attribute":"class","roles":[{"role":"tank","max":1},{"role":"tank","max":1}]}}`, field: "composition.roles[1].role"},
+		{name: "role min above max", rules: `{"alliance":{"player_max_number":5},"composition":{"attribute":"class","roles":[{"role":"tank","min":2,"max":1}]}}`, field: "composition.roles[0].min"},
+		{name: "role without max", rules: `{"alliance":{"player_max_number":5},"composition":{"attribute":"class","roles":[{"role":"tank"}]}}`, field: "composition.roles[0].max"},
+		{name: "roles above team size", rules: `{"alliance":{"player_max_number":2},"composition":{"attribute":"class","roles":[{"role":"tank","min":1,"max":1},{"role":"damage","min":2,"max":2}]}}`, field: "composition.roles"},
+		{name: "attribute rules", rules: `{"alliance":{"player_max_number":4},"attribute_rules":[{"attribute":"gameMode","type":"equal"},{"attribute":"languages","type":"intersect","source":"player"},{"attribute":"level","type":"distance","max_distance":5}]}`},
+		{name: "attribute rule without attribute", rules: `{"alliance":{"player_max_number":4},"attribute_rules":[{"type":"equal"}]}`, field: "attribute_rules[0].attribute"},
+		{name: "unknown attribute rule type", rules: `{"alliance":{"player_max_number":4},"attribute_rules":[{"attribute":"gameMode","type":"like"}]}`, field: "attribute_rules[0].type"},
+		{name: "unknown attribute source", rules: `{"alliance":{"player_max_number":4},"attribute_rules":[{"attribute":"gameMode","type":"equal","source":"party"}]}`, field: "attribute_rules[0].source"},
+		{name: "distance on equal rule", rules: `{"alliance":{"player_max_number":4},"attribute_rules":[{"attribute":"gameMode","type":"equal","max_distance":5}]}`, field: "attribute_rules[0].max_distance"},
+		{name: "client versions", rules: `{"alliance":{"player_max_number":4},"client_version":{"compatible":[{"min":"1.2.0","max":"1.3.9"}]}}`},
+		{name: "version range without max", rules: `{"alliance":{"player_max_number":4},"client_version":{"compatible":[{"min":"1.2.0"}]}}`, field: "client_version.compatible[0]"},
+		{name: "version range upside down", rules: `{"alliance":{"player_max_number":4},"client_version":{"compatible":[{"min":"1.10.0","max":"1.9.0"}]}}`, field: "client_version.compatible[0].min"},
+		{name: "trailing data", rules: `{"alliance":{"player_max_number":4}} {}`, field: "unexpected data"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// act
+			rules, err := NewGameMatchmaker().RulesFromJSON(tt.rules)
+
+			// assert
+			if tt.field == "" {
+				assert.Nil(t, err)
+				assert.IsType(t, GameRules{}, rules)
+				return
+			}
+			assert.Nil(t, rules)
+			assert.Equal(t, codes.InvalidArgument, status.Code(err))
+			assert.Contains(t, status.Convert(err).Message(), tt.field)
+		})
+	}
+}
+
+func TestSimpleRulesFromJSONAllowsEmptyAlliance(t *testing.T) {
+	// act
+	rules, err := New().RulesFromJSON(`null`)
+	demoRules, demoErr := New().RulesFromJSON(`{"shipCountMin":2,"shipCountMax":2}`)
+	_, badErr := New().RulesFromJSON(`{"alliance":{"min_number":-1}}`)
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, GameRules{}, rules)
+	assert.Nil(t, demoErr)
+	assert.Equal(t, GameRules{ShipCountMin: 2, ShipCountMax: 2}, demoRules)
+	assert.Equal(t, codes.InvalidArgument, status.Code(badErr))
+}
+
+func TestValidateTicketInvalidRules(t *testing.T) {
+	// prepare
+	server := MatchFunctionServer{MM: NewGameMatchmaker()}
+	req := &matchfunctiongrpc.ValidateTicketRequest{
+		Ticket: matchfunctiongrpc.MatchfunctionTicketToProtoTicket(newTestTicket("a1")),
+		Rules:  &matchfunctiongrpc.Rules{Json: `{"alliance":{"player_max_number":-4}}`},
+	}
+
+	// act
+	resp, err := server.ValidateTicket(context.Background(), req)
+
+	// assert
+	assert.Nil(t, resp)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.Contains(t, status.Convert(err).Message(), "alliance.player_max_number")
+}
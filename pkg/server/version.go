@@ -0,0 +1,143 @@
+// Copyright (c) 2023 AccelByte Inc. All Rights Reserved.
+// This is licensed software from AccelByte Inc, for limitations
+// and restrictions contact your company contract manager.
+
+package server
+
+import (
+	"strconv"
+	"strings"
+
+	"matchmaking-function-grpc-plugin-server-go/pkg/matchmaker"
+)
+
+// clientVersionAttribute is the ticket attribute carrying the version of the ticket's game client
+const clientVersionAttribute = "client_version"
+
+// ticketClientVersion returns the client version of the ticket, empty when it has none
+func ticketClientVersion(ticket matchmaker.Ticket) string {
+	version, _ := ticket.TicketAttributes[clientVersionAttribute].(string)
+	return version
+}
+
+// compareVersions orders dotted versions such as 1.10.2 part by part, numerically when both parts are numbers. A
+// leading "v" is ignored and a missing part counts as 0. It returns -1, 0 or 1 as a is older, the same or newer than b
+func compareVersions(a string, b string) int {
+	partsA := strings.Split(strings.TrimPrefix(a, "v"), ".")
+	partsB := strings.Split(strings.TrimPrefix(b, "v"), ".")
+	for i := 0; i < len(partsA) || i < len(partsB); i++ {
+		partA, partB := "0", "0"
+		if i < len(partsA) {
+			partA = partsA[i]
+		}
+		if i < len(partsB) {
+			partB = partsB[i]
+		}
+		numberA, errA := strconv.Atoi(partA)
+		numberB, errB := strconv.Atoi(partB)
+		switch {
+		case errA == nil && errB == nil && numberA != numberB:
+			if numberA < numberB {
+				return -1
+			}
+			return 1
+		case (errA != nil || errB != nil) && partA != partB:
+			if partA < partB {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// holds reports whether the version lies in the range
+func (r VersionRange) holds(version string) bool {
+	return version != "" && compareVersions(r.Min, version) <= 0 && compareVersions(version, r.Max) <= 0
+}
+
+// compatible reports whether clients of the versions may share a match, the same version always may
+func (r VersionRule) compatible(a string, b string) bool {
+	if a == b {
+		return true
+	}
+	for _, versions := range r.Compatible {
+		if versions.holds(a) && versions.holds(b) {
+			return true
+		}
+	}
+	return false
+}
+
+// versionsAllow reports whether the tickets may share a match under the rule, counting the pairing as rejected when
+// they may not
+func versionsAllow(scope *Scope, rule VersionRule, tickets []matchmaker.Ticket) bool {
+	for i, ticket := range tickets {
+		for _, other := range tickets[:i] {
+			if !rule.compatible(ticketClientVersion(other), ticketClientVersion(ticket)) {
+				scope.rejectPairing("client_version", other.TicketID, ticket.TicketID)
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// newestVersion returns the newest client version of the tickets, the one a match of them runs
+func newestVersion(tickets []matchmaker.Ticket) string {
+	newest := ""
+	for _, ticket := range tickets {
+		if version := ticketClientVersion(ticket); newest == "" || compareVersions(version, newest) > 0 {
+			newest = version
+		}
+	}
+	return newest
+}
+
+// setServer directs the match to the local DS its tickets asked for, running the newest client version of them
+func setServer(match *matchmaker.Match) {
+	if len(match.Tickets) == 0 {
+		return
+	}
+	match.ServerName = ticketServerName(match.Tickets[0])
+	match.ClientVersion = newestVersion(match.Tickets)
+}
+
+// backfillAllows reports whether the ticket may join the tickets of the backfill ticket's match, asking for the match's
+// server with a client version compatible with the match's and every ticket's
+func backfillAllows(scope *Scope, rule VersionRule, backfillTicket matchmaker.BackfillTicket, tickets []matchmaker.Ticket, ticket matchmaker.Ticket) bool {
+	partial := backfillTicket.PartialMatch
+	if ticketServerName(ticket) != partial.ServerName {
+		scope.rejectPairing("server_name", backfillTicket.TicketID, ticket.TicketID)
+		return false
+	}
+	if partial.ClientVersion != "" && !rule.compatible(partial.ClientVersion, ticketClientVersion(ticket)) {
+		scope.rejectPairing("client_version", backfillTicket.TicketID, ticket.TicketID)
+		return false
+	}
+	return versionsAllow(scope, rule, append(tickets[:len(tickets):len(tickets)], ticket))
+}
+
+// versionWindow is the ticketFilter keeping the client versions of a match compatible with each other
+type versionWindow struct {
+	rule     VersionRule
+	versions map[string]bool
+}
+
+func newVersionWindow(rule VersionRule, seed matchmaker.Ticket) *versionWindow {
+	return &versionWindow{rule: rule, versions: map[string]bool{ticketClientVersion(seed): true}}
+}
+
+func (w *versionWindow) fits(ticket matchmaker.Ticket) bool {
+	version := ticketClientVersion(ticket)
+	for other := range w.versions {
+		if !w.rule.compatible(other, version) {
+			return false
+		}
+	}
+	return true
+}
+
+func (w *versionWindow) add(ticket matchmaker.Ticket) {
+	w.versions[ticketClientVersion(ticket)] = true
+}
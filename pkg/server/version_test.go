@@ -0,0 +1,162 @@
+// Copyright (c) 2023 AccelByte Inc. All Rights Reserved.
+// This is licensed software from AccelByte Inc, for limitations
+// and restrictions contact your company contract manager.
+
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"matchmaking-function-grpc-plugin-server-go/pkg/matchmaker"
+	"matchmaking-function-grpc-plugin-server-go/pkg/player"
+)
+
+func newServerTicket(playerID string, serverName string, clientVersion string) matchmaker.Ticket {
+	attributes := map[string]interface{}{}
+	if serverName != "" {
+		attributes[serverNameAttribute] = serverName
+	}
+	if clientVersion != "" {
+		attributes[clientVersionAttribute] = clientVersion
+	}
+	return newAttributeTicket(playerID, attributes)
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a    string
+		b    string
+		want int
+	}{
+		{a: "1.2.0", b: "1.2.0", want: 0},
+		{a: "1.2", b: "1.2.0", want: 0},
+		{a: "v1.2.0", b: "1.2.0", want: 0},
+		{a: "1.9.0", b: "1.10.0", want: -1},
+		{a: "2.0", b: "1.10.3", want: 1},
+		{a: "1.2.0-beta", b: "1.2.0-rc", want: -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.a+" "+tt.b, func(t *testing.T) {
+			// act
+			got := compareVersions(tt.a, tt.b)
+
+			// assert
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestVersionRuleCompatible(t *testing.T) {
+	// prepare
+	rule := VersionRule{Compatible: []VersionRange{{Min: "1.2.0", Max: "1.3.9"}}}
+
+	// act
+	same := rule.compatible("2.0.0", "2.0.0")
+	inRange := rule.compatible("1.2.5", "1.3.0")
+	outOfRange := rule.compatible("1.2.5", "1.4.0")
+	missing := rule.compatible("", "1.2.5")
+	noRanges := VersionRule{}.compatible("1.2.5", "1.3.0")
+
+	// assert
+	assert.True(t, same)
+	assert.True(t, inRange)
+	assert.False(t, outOfRange)
+	assert.False(t, missing)
+	assert.False(t, noRanges)
+}
+
+func TestGameMatchMakerServerAndVersion(t *testing.T) {
+	// prepare
+	rules := GameRules{
+		AllianceRule: AllianceRule{PlayerMinNumber: 2, PlayerMaxNumber: 2},
+		VersionRule:  VersionRule{Compatible: []VersionRange{{Min: "1.2.0", Max: "1.3.9"}}},
+	}
+	tickets := []matchmaker.Ticket{
+		newServerTicket("a1", "local-ds", "1.2.0"),
+		newServerTicket("b1", "", "1.2.0"),
+		newServerTicket("c1", "", "2.0.0"),
+		newServerTicket("a2", "local-ds", "1.3.1"),
+		newServerTicket("b2", "", "1.3.0"),
+		newServerTicket("c2", "", "1.3.0"),
+	}
+	serverBefore := testutil.ToFloat64(rejectedPairings.WithLabelValues("server_name"))
+	versionBefore := testutil.ToFloat64(rejectedPairings.WithLabelValues("client_version"))
+
+	// act
+	matches := makeTestMatches(t, GameMatchMaker{}, rules, tickets)
+
+	// assert
+	assert.Len(t, matches, 2)
+	for _, match := range matches {
+		if ticketServerName(match.Tickets[0]) == "local-ds" {
+			assert.ElementsMatch(t, []player.ID{"a1", "a2"}, match.Teams[0].UserIDs)
+			assert.Equal(t, "local-ds", match.ServerName)
+			assert.Equal(t, "1.3.1", match.ClientVersion)
+			continue
+		}
+		assert.Len(t, match.Teams[0].UserIDs, 2)
+		assert.Empty(t, match.ServerName)
+		assert.Equal(t, "1.3.0", match.ClientVersion)
+	}
+	// a1 turns down the four tickets of no server, b1 turns down c1, and c1 and c2 turn each other down once
+	assert.Equal(t, serverBefore+4, testutil.ToFloat64(rejectedPairings.WithLabelValues("server_name")))
+	assert.Equal(t, versionBefore+2, testutil.ToFloat64(rejectedPairings.WithLabelValues("client_version")))
+}
+
+func TestMatchMakerServerAndVersion(t *testing.T) {
+	// prepare
+	tickets := []matchmaker.Ticket{
+		newServerTicket("a1", "local-ds", "1.2.0"),
+		newServerTicket("b1", "", "1.2.0"),
+		newServerTicket("c1", "", "1.3.0"),
+		newServerTicket("a2", "local-ds", "1.2.0"),
+		newServerTicket("b2", "", "1.2.0"),
+	}
+
+	// act
+	matches := makeTestMatches(t, MatchMaker{}, GameRules{}, tickets)
+
+	// assert
+	if assert.Len(t, matches, 2) {
+		assert.Equal(t, []player.ID{"a1", "a2"}, matches[0].Teams[0].UserIDs)
+		assert.Equal(t, "local-ds", matches[0].ServerName)
+		assert.Equal(t, "1.2.0", matches[0].ClientVersion)
+		assert.Equal(t, []player.ID{"b1", "b2"}, matches[1].Teams[0].UserIDs)
+		assert.Empty(t, matches[1].ServerName)
+	}
+}
+
+func TestProposeBackfillServerAndVersion(t *testing.T) {
+	// prepare
+	backfillTicket := matchmaker.BackfillTicket{
+		TicketID: GenerateUUID(),
+		PartialMatch: matchmaker.Match{
+			Tickets:       []matchmaker.Ticket{newServerTicket("a1", "local-ds", "1.2.0")},
+			Teams:         []matchmaker.Team{{UserIDs: []player.ID{"a1"}}},
+			ServerName:    "local-ds",
+			ClientVersion: "1.2.0",
+		},
+	}
+	otherServer := newServerTicket("o1", "", "1.2.0")
+	newer := newServerTicket("n1", "local-ds", "1.3.0")
+	pool := []matchmaker.Ticket{otherServer, newer, newServerTicket("s1", "local-ds", "1.2.0")}
+	rules := GameRules{AllianceRule: AllianceRule{PlayerMaxNumber: 3}}
+	scope := NewScope(context.Background(), "")
+	serverBefore := testutil.ToFloat64(rejectedPairings.WithLabelValues("server_name"))
+	versionBefore := testutil.ToFloat64(rejectedPairings.WithLabelValues("client_version"))
+
+	// act
+	proposal, remaining := proposeBackfill(scope, backfillTicket, pool, rules)
+	proposeBackfill(scope, backfillTicket, pool, rules)
+
+	// assert
+	if assert.NotNil(t, proposal) {
+		assert.Equal(t, []matchmaker.Team{{UserIDs: []player.ID{"a1", "s1"}}}, proposal.ProposedTeams)
+	}
+	assert.Equal(t, []string{otherServer.TicketID, newer.TicketID}, ticketIDs(remaining))
+	assert.Equal(t, serverBefore+1, testutil.ToFloat64(rejectedPairings.WithLabelValues("server_name")))
+	assert.Equal(t, versionBefore+1, testutil.ToFloat64(rejectedPairings.WithLabelValues("client_version")))
+}